@@ -3,9 +3,12 @@ module github.com/caelondev/monkey
 go 1.25.4
 
 require (
-	github.com/jwalton/gchalk v1.3.0 // indirect
+	github.com/jwalton/gchalk v1.3.0
+	github.com/sanity-io/litter v1.5.8
+	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211
+)
+
+require (
 	github.com/jwalton/go-supportscolor v1.1.0 // indirect
-	github.com/sanity-io/litter v1.5.8 // indirect
 	golang.org/x/sys v0.0.0-20211004093028-2c5d950f24ef // indirect
-	golang.org/x/term v0.0.0-20210927222741-03fcf44c2211 // indirect
 )
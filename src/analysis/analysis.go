@@ -0,0 +1,543 @@
+package analysis
+
+import (
+	"fmt"
+
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/evaluation"
+	"github.com/caelondev/monkey/src/token"
+)
+
+// NOTE: This is the static side of the evaluator's scoping rules. ---
+// Programs, function bodies and match arms open a scope, if-blocks ---
+// and switch cases don't, and a function body only runs once its ---
+// enclosing scope has been declared, which is why bodies are ---
+// resolved after the rest of their scope ---
+//
+// Every variable declared in a function gets a slot in that function's ---
+// environment, and every identifier that refers to one is given the ---
+// (depth, slot) pair the evaluator uses to reach it without a lookup. ---
+// Globals and natives stay looked up by name, the REPL adds to them ---
+// one line at a time ---
+
+type SymbolKind int
+
+const (
+	VARIABLE SymbolKind = iota
+	CONSTANT
+	FUNCTION
+	PARAMETER
+	NATIVE
+	STRUCT
+	FIELD
+	SELF
+	ENUM
+)
+
+func (k SymbolKind) String() string {
+	switch k {
+	case VARIABLE:
+		return "var"
+	case CONSTANT:
+		return "const"
+	case FUNCTION:
+		return "fn"
+	case PARAMETER:
+		return "parameter"
+	case STRUCT:
+		return "struct"
+	case FIELD:
+		return "field"
+	case SELF:
+		return "self"
+	case ENUM:
+		return "enum"
+	default:
+		return "native"
+	}
+}
+
+type Symbol struct {
+	Name       string
+	Kind       SymbolKind
+	Ident      *ast.Identifier // Declaring identifier, nil for natives and self ---
+	Decl       ast.Node        // Declaring statement or function literal ---
+	Scope      *Scope
+	Slot       int // Only meaningful in a function scope ---
+	References []*ast.Identifier
+}
+
+type Scope struct {
+	Parent  *Scope
+	Node    ast.Node // *ast.Program, a function, *ast.StructStatement or *ast.MatchArm ---
+	Symbols map[string]*Symbol
+	Slots   []string // Slot names, in order, for function scopes ---
+
+	pending    []ast.Node        // Function bodies waiting for the scope to be fully declared ---
+	deferred   []*Scope          // Scopes inside this one that close along with it ---
+	unresolved []*ast.Identifier // References that found nothing when they were reached ---
+}
+
+func (s *Scope) Lookup(name string) *Symbol {
+	for scope := s; scope != nil; scope = scope.Parent {
+		if sym, ok := scope.Symbols[name]; ok {
+			return sym
+		}
+	}
+
+	return nil
+}
+
+// isFunction tells function scopes, whose variables live in slots, from the
+// program and native scopes
+func (s *Scope) isFunction() bool {
+	switch s.Node.(type) {
+	case *ast.FunctionLiteral, *ast.FunctionDeclarationStatement:
+		return true
+	}
+
+	return false
+}
+
+type Severity int
+
+const (
+	ERROR Severity = iota
+	WARNING
+)
+
+type Diagnostic struct {
+	Line     uint
+	Column   uint
+	Severity Severity
+	Message  string
+}
+
+type Info struct {
+	Symbols     []*Symbol                   // Every declaration, in source order ---
+	Scopes      []*Scope                    // Every scope, the program's comes first ---
+	Uses        map[*ast.Identifier]*Symbol // Declarations and references alike ---
+	Undefined   []*ast.Identifier           // References that can never resolve ---
+	Reassigned  []*ast.Identifier           // Assignments to a const ---
+	Diagnostics []Diagnostic
+}
+
+type Options struct {
+	Globals     []string // Names already declared in the global environment ---
+	LateGlobals bool     // Function bodies may use globals declared later on ---
+}
+
+type analyzer struct {
+	info    *Info
+	scope   *Scope
+	options Options
+}
+
+// Analyze resolves every identifier in the program to its declaration. It
+// never fails, problems end up in Info.Diagnostics
+func Analyze(program *ast.Program) *Info {
+	return AnalyzeWith(program, Options{})
+}
+
+// AnalyzeWith is Analyze for a program that runs in an environment which
+// already holds some globals, like a REPL session
+func AnalyzeWith(program *ast.Program, options Options) *Info {
+	a := &analyzer{
+		info:    &Info{Uses: make(map[*ast.Identifier]*Symbol)},
+		options: options,
+	}
+
+	natives := &Scope{Symbols: make(map[string]*Symbol)}
+	for _, name := range evaluation.NativeFunctionNames() {
+		natives.Symbols[name] = &Symbol{Name: name, Kind: NATIVE, Scope: natives}
+	}
+
+	for _, name := range options.Globals {
+		if _, ok := natives.Symbols[name]; !ok {
+			natives.Symbols[name] = &Symbol{Name: name, Kind: VARIABLE, Scope: natives}
+		}
+	}
+
+	a.scope = a.openScope(natives, program)
+	for _, stmt := range program.Statements {
+		a.statement(stmt)
+	}
+	a.closeScope()
+
+	return a.info
+}
+
+func (a *analyzer) openScope(parent *Scope, node ast.Node) *Scope {
+	scope := &Scope{Parent: parent, Node: node, Symbols: make(map[string]*Symbol)}
+	a.info.Scopes = append(a.info.Scopes, scope)
+	return scope
+}
+
+func (a *analyzer) closeScope() {
+	scope := a.scope
+
+	for _, fn := range scope.pending {
+		a.function(fn)
+		a.scope = scope
+	}
+
+	for _, child := range scope.deferred {
+		a.scope = child
+		a.closeScope()
+	}
+	a.scope = scope
+
+	for _, ident := range scope.unresolved {
+		if sym := scope.Lookup(ident.Value); sym != nil {
+			a.use(ident, sym, scope)
+			a.report(ident, ERROR, "'%s' is used before it is declared", ident.Value)
+			continue
+		}
+
+		a.report(ident, ERROR, "Cannot resolve variable '%s'", ident.Value)
+
+		// A later REPL line may still declare the global a body refers to ---
+		if !a.options.LateGlobals || !scope.isFunction() {
+			a.info.Undefined = append(a.info.Undefined, ident)
+		}
+	}
+}
+
+func (a *analyzer) function(node ast.Node) {
+	if structure, ok := node.(*ast.StructStatement); ok {
+		a.structure(structure)
+		return
+	}
+
+	var params []*ast.Parameter
+	var body *ast.BlockStatement
+
+	switch fn := node.(type) {
+	case *ast.FunctionLiteral:
+		params, body = fn.Parameters, fn.Body
+	case *ast.FunctionDeclarationStatement:
+		params, body = fn.Parameters, fn.Body
+	}
+
+	a.scope = a.openScope(a.scope, node)
+
+	for _, param := range params {
+		// A default may use the parameters before it ---
+		a.expression(param.Default)
+		for _, name := range ast.PatternNames(param.Target) {
+			a.declare(name, PARAMETER, node)
+		}
+	}
+
+	if body != nil {
+		a.statement(body)
+	}
+
+	scope := a.scope
+	a.closeScope()
+	markTailCalls(body, true)
+
+	switch fn := node.(type) {
+	case *ast.FunctionLiteral:
+		fn.Slots = scope.Slots
+	case *ast.FunctionDeclarationStatement:
+		fn.Slots = scope.Slots
+	}
+}
+
+// structure resolves a struct once its enclosing scope is declared. Field
+// defaults get a scope of their own, and the methods are opened inside a
+// scope that only holds self, the same environments the evaluator makes
+func (a *analyzer) structure(node *ast.StructStatement) {
+	outer := a.scope
+
+	a.scope = a.openScope(outer, node)
+	for _, field := range node.Fields {
+		a.expression(field.Default)
+		a.declare(field.Ident(), FIELD, node)
+	}
+	a.closeScope()
+
+	a.scope = a.openScope(outer, node)
+	a.scope.Symbols["self"] = &Symbol{Name: "self", Kind: SELF, Decl: node, Scope: a.scope}
+	for _, method := range node.Methods {
+		a.scope.pending = append(a.scope.pending, method)
+	}
+	a.closeScope()
+
+	a.scope = outer
+}
+
+// markTailCalls flags the calls whose value leaves the function as is, the
+// evaluator runs those without growing the stack
+func markTailCalls(node ast.Statement, last bool) {
+	switch node := node.(type) {
+	case *ast.BlockStatement:
+		if node == nil {
+			return
+		}
+		for i, stmt := range node.Statements {
+			markTailCalls(stmt, last && i == len(node.Statements)-1)
+		}
+	case *ast.ReturnStatement:
+		markTailExpression(node.ReturnValue)
+	case *ast.ExpressionStatement:
+		if last {
+			markTailExpression(node.Expression)
+		}
+	case *ast.IfStatement:
+		markTailCalls(node.Consequence, last)
+		markTailCalls(node.Alternative, last)
+	case *ast.SwitchStatement:
+		for _, c := range node.Cases {
+			markTailCalls(c.Body, last)
+		}
+	}
+}
+
+func markTailExpression(node ast.Expression) {
+	switch node := node.(type) {
+	case *ast.CallExpression:
+		node.Tail = true
+	case *ast.PipeExpression:
+		node.Call.Tail = true
+	case *ast.TernaryExpression:
+		markTailExpression(node.Consequence)
+		markTailExpression(node.Alternative)
+	case *ast.BinaryExpression:
+		// The fallback of ?? is the value as it is ---
+		if node.Operator.Type == token.NULLISH {
+			markTailExpression(node.Right)
+		}
+	case *ast.MatchExpression:
+		for _, arm := range node.Arms {
+			markTailExpression(arm.Body)
+		}
+	}
+}
+
+func (a *analyzer) declare(ident *ast.Identifier, kind SymbolKind, decl ast.Node) *Symbol {
+	existing, exists := a.scope.Symbols[ident.Value]
+	if exists && kind != FUNCTION {
+		a.use(ident, existing, a.scope)
+		a.report(ident, ERROR, "Cannot declare '%s' as it already exists", ident.Value)
+		return existing
+	}
+
+	sym := &Symbol{Name: ident.Value, Kind: kind, Ident: ident, Decl: decl, Scope: a.scope}
+
+	// A redeclared function takes over the slot of the one it replaces ---
+	if exists {
+		sym.Slot = existing.Slot
+	} else if a.scope.isFunction() {
+		sym.Slot = len(a.scope.Slots)
+		a.scope.Slots = append(a.scope.Slots, ident.Value)
+	}
+
+	a.scope.Symbols[ident.Value] = sym
+	a.info.Symbols = append(a.info.Symbols, sym)
+	a.info.Uses[ident] = sym
+	a.bind(ident, sym, a.scope)
+
+	return sym
+}
+
+func (a *analyzer) reference(ident *ast.Identifier) {
+	if sym := a.scope.Lookup(ident.Value); sym != nil {
+		a.use(ident, sym, a.scope)
+		return
+	}
+
+	ident.Binding = nil
+	a.scope.unresolved = append(a.scope.unresolved, ident)
+}
+
+// checkWritable reports assignments to a const. Only the variable itself is
+// protected, elements of a const array can still change
+func (a *analyzer) checkWritable(target ast.Expression) {
+	ident, ok := target.(*ast.Identifier)
+	if !ok {
+		return
+	}
+
+	if sym := a.info.Uses[ident]; sym != nil && sym.Kind == CONSTANT {
+		a.report(ident, ERROR, "Cannot assign to '%s' as it is a constant", ident.Value)
+		a.info.Reassigned = append(a.info.Reassigned, ident)
+	}
+}
+
+func (a *analyzer) use(ident *ast.Identifier, sym *Symbol, from *Scope) {
+	a.info.Uses[ident] = sym
+	sym.References = append(sym.References, ident)
+	a.bind(ident, sym, from)
+}
+
+// bind records where the evaluator finds the symbol, counted from the scope
+// the identifier appears in. If-blocks share their function's environment,
+// so every scope crossed is one environment
+func (a *analyzer) bind(ident *ast.Identifier, sym *Symbol, from *Scope) {
+	if !sym.Scope.isFunction() {
+		ident.Binding = nil
+		return
+	}
+
+	depth := 0
+	for scope := from; scope != sym.Scope; scope = scope.Parent {
+		depth++
+	}
+
+	ident.Binding = &ast.Binding{Depth: depth, Slot: sym.Slot}
+}
+
+func (a *analyzer) report(node ast.Node, severity Severity, format string, args ...any) {
+	a.info.Diagnostics = append(a.info.Diagnostics, Diagnostic{
+		Line:     node.GetLine(),
+		Column:   node.GetColumn(),
+		Severity: severity,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+func (a *analyzer) statement(node ast.Statement) {
+	switch node := node.(type) {
+	case nil:
+		return
+	case *ast.VarStatement:
+		// The value is evaluated before the names exist ---
+		a.expression(node.Value)
+
+		kind := VARIABLE
+		if node.Constant {
+			kind = CONSTANT
+		}
+		for _, target := range node.Targets {
+			for _, name := range ast.PatternNames(target) {
+				a.declare(name, kind, node)
+			}
+		}
+	case *ast.FunctionDeclarationStatement:
+		a.declare(node.Name, FUNCTION, node)
+		a.scope.pending = append(a.scope.pending, node)
+	case *ast.StructStatement:
+		a.declare(node.Name, STRUCT, node)
+		a.scope.pending = append(a.scope.pending, node)
+	case *ast.EnumStatement:
+		a.declare(node.Name, ENUM, node)
+	case *ast.ReturnStatement:
+		a.expression(node.ReturnValue)
+	case *ast.ExpressionStatement:
+		a.expression(node.Expression)
+	case *ast.BlockStatement:
+		for _, stmt := range node.Statements {
+			a.statement(stmt)
+		}
+	case *ast.IfStatement:
+		a.expression(node.Condition)
+		a.statement(node.Consequence)
+		a.statement(node.Alternative)
+	case *ast.SwitchStatement:
+		a.expression(node.Subject)
+		for _, c := range node.Cases {
+			for _, value := range c.Values {
+				a.expression(value)
+			}
+			a.statement(c.Body)
+		}
+	case *ast.BatchAssignmentStatement:
+		a.expression(node.NewValue)
+		for _, target := range node.Assignees {
+			for _, assignee := range ast.PatternNames(target) {
+				a.reference(assignee)
+				a.checkWritable(assignee)
+			}
+		}
+	}
+}
+
+func (a *analyzer) expression(node ast.Expression) {
+	switch node := node.(type) {
+	case nil:
+		return
+	case *ast.Identifier:
+		a.reference(node)
+	case *ast.UnaryExpression:
+		a.expression(node.Right)
+	case *ast.BinaryExpression:
+		a.expression(node.Left)
+		a.expression(node.Right)
+	case *ast.TernaryExpression:
+		a.expression(node.Condition)
+		a.expression(node.Consequence)
+		a.expression(node.Alternative)
+	case *ast.FunctionLiteral:
+		a.scope.pending = append(a.scope.pending, node)
+	case *ast.CallExpression:
+		a.expression(node.Function)
+		for _, arg := range node.Arguments {
+			a.expression(arg)
+		}
+	case *ast.PipeExpression:
+		a.expression(node.Call)
+	case *ast.ArrayLiteral:
+		for _, elem := range node.Elements {
+			a.expression(elem)
+		}
+	case *ast.SpreadExpression:
+		a.expression(node.Value)
+	case *ast.NamedArgument:
+		// The name belongs to the callee's parameters, not this scope ---
+		a.expression(node.Value)
+	case *ast.IndexExpression:
+		a.expression(node.Target)
+		a.expression(node.Index)
+	case *ast.MemberExpression:
+		a.expression(node.Target)
+	case *ast.MatchExpression:
+		a.expression(node.Subject)
+		for _, arm := range node.Arms {
+			a.matchArm(arm)
+		}
+	case *ast.SliceExpression:
+		a.expression(node.Target)
+		a.expression(node.Start)
+		a.expression(node.End)
+		a.expression(node.Step)
+	case *ast.AssignmentExpression:
+		a.expression(node.NewValue)
+		a.expression(node.Assignee)
+		a.checkWritable(node.Assignee)
+	case *ast.UpdateExpression:
+		a.expression(node.Target)
+		a.checkWritable(node.Target)
+	case *ast.HashLiteral:
+		for i, key := range node.Keys {
+			a.expression(key)
+			a.expression(node.Values[i])
+		}
+	}
+}
+
+// matchArm resolves one arm of a match. The names its pattern binds live in
+// a scope of their own, which is closed along with the one around it so
+// functions in the arm still see everything declared after the match
+func (a *analyzer) matchArm(arm *ast.MatchArm) {
+	// The structs and enums a pattern names are looked up around it ---
+	ast.Inspect(arm.Pattern, func(node ast.Node) bool {
+		if pattern, ok := node.(*ast.ConstructorPattern); ok {
+			a.expression(pattern.Type)
+		}
+		return true
+	})
+
+	outer := a.scope
+	a.scope = a.openScope(outer, arm)
+
+	for _, name := range ast.PatternNames(arm.Pattern) {
+		a.declare(name, VARIABLE, arm)
+	}
+	a.expression(arm.Guard)
+	a.expression(arm.Body)
+
+	outer.deferred = append(outer.deferred, a.scope)
+	a.scope = outer
+}
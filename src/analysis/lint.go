@@ -0,0 +1,272 @@
+package analysis
+
+import (
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/caelondev/monkey/src/ast"
+)
+
+// Lint adds the style and correctness warnings on top of what Analyze found.
+// The result is sorted by position
+func Lint(program *ast.Program, info *Info) []Diagnostic {
+	l := &linter{diagnostics: append([]Diagnostic{}, info.Diagnostics...)}
+
+	l.unusedSymbols(info)
+	l.shadowedSymbols(info)
+	l.unreachableCode(program)
+	l.argumentCounts(program, info)
+	l.enumCoverage(program, info)
+
+	sort.SliceStable(l.diagnostics, func(i, j int) bool {
+		a, b := l.diagnostics[i], l.diagnostics[j]
+		return a.Line < b.Line || (a.Line == b.Line && a.Column < b.Column)
+	})
+
+	return l.diagnostics
+}
+
+type linter struct {
+	diagnostics []Diagnostic
+}
+
+func (l *linter) warn(node ast.Node, format string, args ...any) {
+	l.diagnostics = append(l.diagnostics, Diagnostic{
+		Line:     node.GetLine(),
+		Column:   node.GetColumn(),
+		Severity: WARNING,
+		Message:  fmt.Sprintf(format, args...),
+	})
+}
+
+// Names starting with an underscore are unused on purpose ---
+func (l *linter) unusedSymbols(info *Info) {
+	for _, sym := range info.Symbols {
+		if len(sym.References) != 0 || strings.HasPrefix(sym.Name, "_") {
+			continue
+		}
+
+		switch sym.Kind {
+		case VARIABLE:
+			l.warn(sym.Ident, "Variable '%s' is declared but never used", sym.Name)
+		case CONSTANT:
+			l.warn(sym.Ident, "Constant '%s' is declared but never used", sym.Name)
+		case PARAMETER:
+			l.warn(sym.Ident, "Parameter '%s' is never used", sym.Name)
+		}
+	}
+}
+
+func (l *linter) shadowedSymbols(info *Info) {
+	for _, sym := range info.Symbols {
+		outer := sym.Scope.Parent.Lookup(sym.Name)
+
+		// Fields are only ever reached through the instance ---
+		if sym.Kind == FIELD {
+			continue
+		}
+
+		// Natives may be shadowed on purpose, only user declarations count ---
+		if outer == nil || outer.Kind == NATIVE || outer.Ident == nil {
+			continue
+		}
+
+		l.warn(sym.Ident, "'%s' shadows the declaration at Ln %d:%d",
+			sym.Name, outer.Ident.Token.Line, outer.Ident.Token.Column)
+	}
+}
+
+func (l *linter) unreachableCode(program *ast.Program) {
+	l.unreachableIn(program.Statements)
+
+	ast.Inspect(program, func(node ast.Node) bool {
+		if block, ok := node.(*ast.BlockStatement); ok {
+			l.unreachableIn(block.Statements)
+		}
+		return true
+	})
+}
+
+func (l *linter) unreachableIn(statements []ast.Statement) {
+	for i, stmt := range statements {
+		if _, ok := stmt.(*ast.ReturnStatement); ok && i != len(statements)-1 {
+			l.warn(statements[i+1], "Unreachable code after return")
+			return
+		}
+	}
+}
+
+func (l *linter) argumentCounts(program *ast.Program, info *Info) {
+	ast.Inspect(program, func(node ast.Node) bool {
+		call, ok := node.(*ast.CallExpression)
+		if !ok {
+			return true
+		}
+
+		ident, ok := call.Function.(*ast.Identifier)
+		if !ok {
+			return true
+		}
+
+		params, ok := parametersOf(info.Uses[ident])
+		if !ok {
+			return true
+		}
+
+		sym := info.Uses[ident]
+		min, max := ast.Arity(params)
+
+		count := 0
+		for _, arg := range call.Arguments {
+			switch arg := arg.(type) {
+			case *ast.SpreadExpression:
+				// A spread argument's length is only known at runtime ---
+				return true
+			case *ast.NamedArgument:
+				if !hasParameter(params, arg.Name) {
+					l.warn(arg, "'%s' has no parameter named '%s'", sym.Name, arg.Name)
+					return true
+				}
+			}
+			count++
+		}
+
+		switch {
+		case min == max && count != min:
+			l.warn(ident, "'%s' expects %s, got %d",
+				sym.Name, arguments(min), count)
+		case count < min:
+			l.warn(ident, "'%s' expects at least %s, got %d",
+				sym.Name, arguments(min), count)
+		case max != -1 && count > max:
+			l.warn(ident, "'%s' expects at most %s, got %d",
+				sym.Name, arguments(max), count)
+		}
+
+		return true
+	})
+}
+
+// parametersOf finds what calling sym takes. A struct takes its fields, or
+// what its init method takes when it has one
+// arguments spells out a count of arguments, "1 argument" or "2 arguments"
+func arguments(n int) string {
+	if n == 1 {
+		return "1 argument"
+	}
+	return fmt.Sprintf("%d arguments", n)
+}
+
+func parametersOf(sym *Symbol) ([]*ast.Parameter, bool) {
+	switch {
+	case sym == nil:
+		return nil, false
+	case sym.Kind == FUNCTION:
+		return sym.Decl.(*ast.FunctionDeclarationStatement).Parameters, true
+	case sym.Kind == STRUCT:
+		decl := sym.Decl.(*ast.StructStatement)
+		for _, method := range decl.Methods {
+			if method.Name.Value == "init" {
+				return method.Parameters, true
+			}
+		}
+		return decl.Fields, true
+	}
+
+	return nil, false
+}
+
+// hasParameter tells whether name can be passed by name, rest parameters
+// only take positional arguments
+func hasParameter(params []*ast.Parameter, name string) bool {
+	for _, param := range params {
+		if param.Ident() != nil && param.Ident().Value == name && !param.Rest {
+			return true
+		}
+	}
+
+	return false
+}
+
+// enumCoverage warns about a match on an enum's variants that leaves some of
+// them out and has no arm that catches the rest
+func (l *linter) enumCoverage(program *ast.Program, info *Info) {
+	ast.Inspect(program, func(node ast.Node) bool {
+		match, ok := node.(*ast.MatchExpression)
+		if !ok {
+			return true
+		}
+
+		var enum *ast.EnumStatement
+		covered := make(map[string]bool)
+
+		for _, arm := range match.Arms {
+			// A guard may turn the value down, so its arm covers nothing ---
+			if arm.Guard != nil {
+				continue
+			}
+
+			if catchesAll(arm.Pattern) {
+				return true
+			}
+
+			pattern, ok := arm.Pattern.(*ast.ConstructorPattern)
+			if !ok {
+				continue
+			}
+
+			member, ok := pattern.Type.(*ast.MemberExpression)
+			if !ok {
+				continue
+			}
+
+			ident, ok := member.Target.(*ast.Identifier)
+			if !ok {
+				continue
+			}
+
+			sym := info.Uses[ident]
+			if sym == nil || sym.Kind != ENUM || (enum != nil && sym.Decl != enum) {
+				continue
+			}
+			enum = sym.Decl.(*ast.EnumStatement)
+
+			if slices.IndexFunc(pattern.Elements, isRefutable) == -1 && slices.IndexFunc(pattern.Values, isRefutable) == -1 {
+				covered[member.Property] = true
+			}
+		}
+
+		if enum == nil {
+			return true
+		}
+
+		var missing []string
+		for _, variant := range enum.Variants {
+			if !covered[variant.Name.Value] {
+				missing = append(missing, enum.Name.Value+"."+variant.Name.Value)
+			}
+		}
+
+		if len(missing) != 0 {
+			l.warn(match, "Match on '%s' doesn't cover %s, add their arms or a _ arm",
+				enum.Name.Value, strings.Join(missing, ", "))
+		}
+
+		return true
+	})
+}
+
+// catchesAll tells whether a pattern fits every value
+func catchesAll(pattern ast.Pattern) bool {
+	switch pattern.(type) {
+	case *ast.WildcardPattern, *ast.Identifier:
+		return true
+	}
+	return false
+}
+
+func isRefutable(pattern ast.Pattern) bool {
+	return !catchesAll(pattern)
+}
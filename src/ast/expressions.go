@@ -2,6 +2,8 @@ package ast
 
 import (
 	"bytes"
+	"math/big"
+	"strings"
 
 	"github.com/caelondev/monkey/src/token"
 )
@@ -52,6 +54,76 @@ func (n *NumberLiteral) TokenLiteral() string {
 	return n.Token.Literal
 }
 
+// ---------------- IntegerLiteral ----------------
+type IntegerLiteral struct {
+	Token token.Token
+	Value int64
+}
+
+func (n *IntegerLiteral) GetLine() uint {
+	return n.Token.Line
+}
+func (n *IntegerLiteral) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *IntegerLiteral) expressionNode() {}
+func (n *IntegerLiteral) String() string {
+	var out bytes.Buffer
+	out.WriteString(n.Token.Literal)
+	return out.String()
+}
+func (n *IntegerLiteral) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- BigIntLiteral ----------------
+type BigIntLiteral struct {
+	Token token.Token
+	Value *big.Int
+}
+
+func (n *BigIntLiteral) GetLine() uint {
+	return n.Token.Line
+}
+func (n *BigIntLiteral) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *BigIntLiteral) expressionNode() {}
+func (n *BigIntLiteral) String() string {
+	var out bytes.Buffer
+	out.WriteString(n.Token.Literal)
+	return out.String()
+}
+func (n *BigIntLiteral) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- DecimalLiteral ----------------
+type DecimalLiteral struct {
+	Token    token.Token
+	Unscaled *big.Int // 1.10d is 110 with a scale of 2 ---
+	Scale    int
+}
+
+func (n *DecimalLiteral) GetLine() uint {
+	return n.Token.Line
+}
+func (n *DecimalLiteral) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *DecimalLiteral) expressionNode() {}
+func (n *DecimalLiteral) String() string {
+	var out bytes.Buffer
+	out.WriteString(n.Token.Literal)
+	return out.String()
+}
+func (n *DecimalLiteral) TokenLiteral() string {
+	return n.Token.Literal
+}
+
 // ---------------- NilLiteral ----------------
 type NilLiteral struct {
 	Token token.Token
@@ -76,8 +148,16 @@ func (n *NilLiteral) TokenLiteral() string {
 
 // ---------------- Identifier ----------------
 type Identifier struct {
-	Token token.Token
-	Value string
+	Token   token.Token
+	Value   string
+	Binding *Binding // Filled in by the resolver, nil means look it up by name ---
+}
+
+// Binding is where the resolver found a variable: Depth environments out
+// from where it's used, at Slot within that environment
+type Binding struct {
+	Depth int
+	Slot  int
 }
 
 func (i *Identifier) GetLine() uint {
@@ -201,11 +281,104 @@ func (te *TernaryExpression) TokenLiteral() string {
 	return te.Token.Literal
 }
 
+// ---------------- Parameter ----------------
+type Parameter struct {
+	Target  Pattern    // A name, or a pattern that takes the argument apart ---
+	Default Expression // nil for a required parameter ---
+	Rest    bool       // ...name collects the arguments left over ---
+}
+
+func (n *Parameter) GetLine() uint {
+	return n.Target.GetLine()
+}
+func (n *Parameter) GetColumn() uint {
+	return n.Target.GetColumn()
+}
+
+func (n *Parameter) String() string {
+	switch {
+	case n.Rest:
+		return "..." + n.Target.String()
+	case n.Default != nil:
+		return n.Target.String() + " = " + n.Default.String()
+	}
+	return n.Target.String()
+}
+func (n *Parameter) TokenLiteral() string {
+	return n.Target.TokenLiteral()
+}
+
+// Ident is the parameter's name, nil when it destructures its argument and
+// so can't be passed by name
+func (n *Parameter) Ident() *Identifier {
+	ident, _ := n.Target.(*Identifier)
+	return ident
+}
+
+// Arity gives how many arguments a parameter list accepts, max is -1 when a
+// rest parameter takes any number
+func Arity(params []*Parameter) (min, max int) {
+	for _, param := range params {
+		switch {
+		case param.Rest:
+			return min, -1
+		case param.Default == nil:
+			min++
+		}
+	}
+
+	return min, len(params)
+}
+
+// ---------------- SpreadExpression ----------------
+type SpreadExpression struct {
+	Token token.Token // ... ---
+	Value Expression  // Must be an array when evaluated ---
+}
+
+func (n *SpreadExpression) GetLine() uint {
+	return n.Token.Line
+}
+func (n *SpreadExpression) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *SpreadExpression) expressionNode() {}
+func (n *SpreadExpression) String() string {
+	return "..." + n.Value.String()
+}
+func (n *SpreadExpression) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- NamedArgument ----------------
+type NamedArgument struct {
+	Token token.Token // The name ---
+	Name  string
+	Value Expression
+}
+
+func (n *NamedArgument) GetLine() uint {
+	return n.Token.Line
+}
+func (n *NamedArgument) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *NamedArgument) expressionNode() {}
+func (n *NamedArgument) String() string {
+	return n.Name + ": " + n.Value.String()
+}
+func (n *NamedArgument) TokenLiteral() string {
+	return n.Token.Literal
+}
+
 // ---------------- FunctionLiteral ----------------
 type FunctionLiteral struct {
 	Token      token.Token
-	Parameters []*Identifier
+	Parameters []*Parameter
 	Body       *BlockStatement
+	Slots      []string // Name of every resolved slot in the body's environment ---
 }
 
 func (fl *FunctionLiteral) GetLine() uint {
@@ -237,11 +410,65 @@ func (fl *FunctionLiteral) TokenLiteral() string {
 	return fl.Token.Literal
 }
 
+// ---------------- PipeExpression ----------------
+type PipeExpression struct {
+	Token token.Token     // |> ---
+	Call  *CallExpression // What runs, the piped value is its first argument ---
+	Bare  bool            // The stage was a plain function, x |> f, not a call ---
+}
+
+func (pe *PipeExpression) GetLine() uint {
+	return pe.Token.Line
+}
+func (pe *PipeExpression) GetColumn() uint {
+	return pe.Token.Column
+}
+
+// Value is the expression on the left of the |>
+func (pe *PipeExpression) Value() Expression {
+	return pe.Call.Arguments[0]
+}
+
+// Arguments are the ones written in the stage, without the piped value
+func (pe *PipeExpression) Arguments() []Expression {
+	return pe.Call.Arguments[1:]
+}
+
+func (pe *PipeExpression) expressionNode() {}
+func (pe *PipeExpression) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(pe.Value().String())
+	out.WriteString(" |> ")
+	out.WriteString(pe.Call.Function.String())
+
+	if !pe.Bare {
+		args := make([]string, len(pe.Arguments()))
+		for i, arg := range pe.Arguments() {
+			args[i] = arg.String()
+		}
+
+		if pe.Call.Optional {
+			out.WriteString("?.")
+		}
+		out.WriteString("(")
+		out.WriteString(strings.Join(args, ", "))
+		out.WriteString(")")
+	}
+
+	return out.String()
+}
+func (pe *PipeExpression) TokenLiteral() string {
+	return pe.Token.Literal
+}
+
 // ---------------- CallExpression ----------------
 type CallExpression struct {
 	Token     token.Token
 	Function  Expression
 	Arguments []Expression
+	Tail      bool // Its value is what the enclosing function returns ---
+	Optional  bool // f?.(), nil when the function is nil ---
 }
 
 func (ce *CallExpression) GetLine() uint {
@@ -255,6 +482,9 @@ func (ce *CallExpression) expressionNode() {}
 func (ce *CallExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString(ce.Function.String())
+	if ce.Optional {
+		out.WriteString("?.")
+	}
 	out.WriteString("(")
 	for i, arg := range ce.Arguments {
 		if i > 0 {
@@ -316,11 +546,16 @@ func (n *InfinityLiteral) TokenLiteral() string {
 
 // ---------------- AssignmentExpression ----------------
 type AssignmentExpression struct {
-	Token    token.Token
-	Assignee Expression
+	Token    token.Token // = or a compound operator like += ---
+	Assignee Expression  // An identifier or an index expression ---
 	NewValue Expression
 }
 
+// IsCompound tells a += b from a = b
+func (n *AssignmentExpression) IsCompound() bool {
+	return n.Token.Type != token.ASSIGNMENT
+}
+
 func (n *AssignmentExpression) GetLine() uint {
 	return n.Token.Line
 }
@@ -332,7 +567,7 @@ func (n *AssignmentExpression) expressionNode() {}
 func (n *AssignmentExpression) String() string {
 	var out bytes.Buffer
 	out.WriteString(n.Assignee.String())
-	out.WriteString(" = ")
+	out.WriteString(" " + n.Token.Literal + " ")
 	out.WriteString(n.NewValue.String())
 	return out.String()
 }
@@ -377,9 +612,10 @@ func (n *ArrayLiteral) TokenLiteral() string {
 
 // ---------------- IndexExpression ----------------
 type IndexExpression struct {
-	Token  token.Token
-	Index  Expression
-	Target Expression
+	Token    token.Token
+	Index    Expression
+	Target   Expression
+	Optional bool // a?[i], nil when the target is nil ---
 }
 
 func (n *IndexExpression) GetLine() uint {
@@ -394,6 +630,9 @@ func (n *IndexExpression) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(n.Target.String())
+	if n.Optional {
+		out.WriteString("?")
+	}
 	out.WriteString("[")
 	out.WriteString(n.Index.String())
 	out.WriteString("]")
@@ -403,3 +642,191 @@ func (n *IndexExpression) String() string {
 func (n *IndexExpression) TokenLiteral() string {
 	return n.Token.Literal
 }
+
+// ---------------- MemberExpression ----------------
+type MemberExpression struct {
+	Token    token.Token // . or ?. ---
+	Target   Expression
+	Property string
+	Optional bool // a?.b, nil when the target is nil ---
+}
+
+func (n *MemberExpression) GetLine() uint {
+	return n.Token.Line
+}
+func (n *MemberExpression) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *MemberExpression) expressionNode() {}
+func (n *MemberExpression) String() string {
+	if n.Optional {
+		return n.Target.String() + "?." + n.Property
+	}
+	return n.Target.String() + "." + n.Property
+}
+func (n *MemberExpression) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- SliceExpression ----------------
+type SliceExpression struct {
+	Token    token.Token // [ or ?[ ---
+	Target   Expression
+	Start    Expression // nil when left out, as are End and Step ---
+	End      Expression
+	Step     Expression
+	Optional bool // a?[i:j], nil when the target is nil ---
+}
+
+func (n *SliceExpression) GetLine() uint {
+	return n.Token.Line
+}
+func (n *SliceExpression) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *SliceExpression) expressionNode() {}
+func (n *SliceExpression) String() string {
+	var out bytes.Buffer
+
+	bound := func(expr Expression) {
+		if expr != nil {
+			out.WriteString(expr.String())
+		}
+	}
+
+	out.WriteString(n.Target.String())
+	if n.Optional {
+		out.WriteString("?")
+	}
+	out.WriteString("[")
+	bound(n.Start)
+	out.WriteString(":")
+	bound(n.End)
+	if n.Step != nil {
+		out.WriteString(":")
+		bound(n.Step)
+	}
+	out.WriteString("]")
+
+	return out.String()
+}
+func (n *SliceExpression) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- UpdateExpression ----------------
+type UpdateExpression struct {
+	Token    token.Token
+	Operator token.Token // ++ or -- ---
+	Target   Expression
+	Prefix   bool // ++x gives the new value, x++ the old one ---
+}
+
+func (n *UpdateExpression) GetLine() uint {
+	return n.Token.Line
+}
+func (n *UpdateExpression) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *UpdateExpression) expressionNode() {}
+func (n *UpdateExpression) String() string {
+	if n.Prefix {
+		return n.Operator.Literal + n.Target.String()
+	}
+	return n.Target.String() + n.Operator.Literal
+}
+func (n *UpdateExpression) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- HashLiteral ----------------
+type HashLiteral struct {
+	Token  token.Token
+	Keys   []Expression
+	Values []Expression // Values[i] belongs to Keys[i], in source order ---
+}
+
+func (n *HashLiteral) GetLine() uint {
+	return n.Token.Line
+}
+func (n *HashLiteral) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *HashLiteral) expressionNode() {}
+func (n *HashLiteral) String() string {
+	var out bytes.Buffer
+
+	out.WriteString("{")
+
+	for i, key := range n.Keys {
+		out.WriteString(key.String())
+		out.WriteString(": ")
+		out.WriteString(n.Values[i].String())
+		if i != len(n.Keys)-1 {
+			out.WriteString(", ")
+		}
+	}
+
+	out.WriteString("}")
+
+	return out.String()
+}
+func (n *HashLiteral) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- MatchExpression ----------------
+type MatchExpression struct {
+	Token    token.Token // match ---
+	Subject  Expression
+	Arms     []*MatchArm // Tried in order, the first that matches is taken ---
+	EndToken token.Token // The closing }, comments before it stay inside ---
+}
+
+func (n *MatchExpression) GetLine() uint {
+	return n.Token.Line
+}
+func (n *MatchExpression) GetColumn() uint {
+	return n.Token.Column
+}
+
+func (n *MatchExpression) expressionNode() {}
+func (n *MatchExpression) String() string {
+	arms := make([]string, len(n.Arms))
+	for i, arm := range n.Arms {
+		arms[i] = arm.String()
+	}
+
+	return "match " + n.Subject.String() + " { " + strings.Join(arms, ", ") + " }"
+}
+func (n *MatchExpression) TokenLiteral() string {
+	return n.Token.Literal
+}
+
+// ---------------- MatchArm ----------------
+type MatchArm struct {
+	Pattern Pattern
+	Guard   Expression // nil for an arm without if ---
+	Body    Expression
+}
+
+func (n *MatchArm) GetLine() uint {
+	return n.Pattern.GetLine()
+}
+func (n *MatchArm) GetColumn() uint {
+	return n.Pattern.GetColumn()
+}
+
+func (n *MatchArm) String() string {
+	if n.Guard != nil {
+		return n.Pattern.String() + " if " + n.Guard.String() + " => " + n.Body.String()
+	}
+	return n.Pattern.String() + " => " + n.Body.String()
+}
+func (n *MatchArm) TokenLiteral() string {
+	return n.Pattern.TokenLiteral()
+}
@@ -0,0 +1,225 @@
+package ast
+
+import (
+	"bytes"
+	"strings"
+
+	"github.com/caelondev/monkey/src/token"
+)
+
+// NOTE: Patterns are what a value can be bound to. A plain identifier ---
+// takes the value whole, array and hash patterns take it apart and ---
+// bind the pieces, nesting as deep as the value does. Match arms also ---
+// take the patterns below them, which can fail to fit a value ---
+
+type Pattern interface {
+	Node
+	patternNode()
+}
+
+func (i *Identifier) patternNode() {}
+
+// ---------------- ArrayPattern ----------------
+type ArrayPattern struct {
+	Token    token.Token // [ ---
+	Elements []Pattern
+	Rest     *Identifier // ...name collects the elements left over, nil for none ---
+}
+
+func (ap *ArrayPattern) GetLine() uint {
+	return ap.Token.Line
+}
+func (ap *ArrayPattern) GetColumn() uint {
+	return ap.Token.Column
+}
+
+func (ap *ArrayPattern) patternNode() {}
+func (ap *ArrayPattern) String() string {
+	parts := make([]string, 0, len(ap.Elements)+1)
+	for _, elem := range ap.Elements {
+		parts = append(parts, elem.String())
+	}
+	if ap.Rest != nil {
+		parts = append(parts, "..."+ap.Rest.String())
+	}
+
+	return "[" + strings.Join(parts, ", ") + "]"
+}
+func (ap *ArrayPattern) TokenLiteral() string {
+	return ap.Token.Literal
+}
+
+// ---------------- HashPattern ----------------
+type HashPattern struct {
+	Token  token.Token      // { ---
+	Keys   []*StringLiteral // Token is the name itself for {name} and {name: pattern} ---
+	Values []Pattern
+	Rest   *Identifier // ...name collects the pairs left over, nil for none ---
+}
+
+func (hp *HashPattern) GetLine() uint {
+	return hp.Token.Line
+}
+func (hp *HashPattern) GetColumn() uint {
+	return hp.Token.Column
+}
+
+func (hp *HashPattern) patternNode() {}
+func (hp *HashPattern) String() string {
+	var out bytes.Buffer
+	out.WriteString("{")
+
+	for i, key := range hp.Keys {
+		if i != 0 {
+			out.WriteString(", ")
+		}
+
+		if hp.IsShorthand(i) {
+			out.WriteString(key.Value)
+			continue
+		}
+
+		if key.Token.Type == token.STRING {
+			out.WriteString("\"" + key.Value + "\"")
+		} else {
+			out.WriteString(key.Value)
+		}
+		out.WriteString(": ")
+		out.WriteString(hp.Values[i].String())
+	}
+
+	if hp.Rest != nil {
+		if len(hp.Keys) != 0 {
+			out.WriteString(", ")
+		}
+		out.WriteString("..." + hp.Rest.String())
+	}
+
+	out.WriteString("}")
+	return out.String()
+}
+func (hp *HashPattern) TokenLiteral() string {
+	return hp.Token.Literal
+}
+
+// IsShorthand tells whether the i-th pair was written {name}, binding the
+// key to a variable of the same name
+func (hp *HashPattern) IsShorthand(i int) bool {
+	ident, ok := hp.Values[i].(*Identifier)
+	return ok && hp.Keys[i].Token.Type == token.IDENTIFIER && ident.Value == hp.Keys[i].Value
+}
+
+// ---------------- WildcardPattern ----------------
+type WildcardPattern struct {
+	Token token.Token // _ ---
+}
+
+func (wp *WildcardPattern) GetLine() uint {
+	return wp.Token.Line
+}
+func (wp *WildcardPattern) GetColumn() uint {
+	return wp.Token.Column
+}
+
+func (wp *WildcardPattern) patternNode() {}
+func (wp *WildcardPattern) String() string {
+	return "_"
+}
+func (wp *WildcardPattern) TokenLiteral() string {
+	return wp.Token.Literal
+}
+
+// ---------------- LiteralPattern ----------------
+type LiteralPattern struct {
+	Value Expression // A number, string, boolean or nil literal, maybe negated ---
+}
+
+func (lp *LiteralPattern) GetLine() uint {
+	return lp.Value.GetLine()
+}
+func (lp *LiteralPattern) GetColumn() uint {
+	return lp.Value.GetColumn()
+}
+
+func (lp *LiteralPattern) patternNode() {}
+func (lp *LiteralPattern) String() string {
+	return lp.Value.String()
+}
+func (lp *LiteralPattern) TokenLiteral() string {
+	return lp.Value.TokenLiteral()
+}
+
+// ---------------- ConstructorPattern ----------------
+type ConstructorPattern struct {
+	Type     Expression // Point for a struct, Color.Blue for an enum variant ---
+	Elements []Pattern  // Fields in declaration order ---
+	Keys     []*Identifier
+	Values   []Pattern // Fields by name, after the positional ones ---
+}
+
+func (cp *ConstructorPattern) GetLine() uint {
+	return cp.Type.GetLine()
+}
+func (cp *ConstructorPattern) GetColumn() uint {
+	return cp.Type.GetColumn()
+}
+
+func (cp *ConstructorPattern) patternNode() {}
+func (cp *ConstructorPattern) String() string {
+	if !cp.HasParentheses() {
+		return cp.Type.String()
+	}
+
+	parts := make([]string, 0, len(cp.Elements)+len(cp.Keys))
+	for _, elem := range cp.Elements {
+		parts = append(parts, elem.String())
+	}
+	for i, key := range cp.Keys {
+		parts = append(parts, key.Value+": "+cp.Values[i].String())
+	}
+
+	return cp.Type.String() + "(" + strings.Join(parts, ", ") + ")"
+}
+func (cp *ConstructorPattern) TokenLiteral() string {
+	return cp.Type.TokenLiteral()
+}
+
+// HasParentheses tells whether the pattern is written with a field list. A
+// struct always needs one, a bare Point would bind a name instead
+func (cp *ConstructorPattern) HasParentheses() bool {
+	_, variant := cp.Type.(*MemberExpression)
+	return !variant || len(cp.Elements) != 0 || len(cp.Keys) != 0
+}
+
+// PatternNames lists every identifier a pattern binds, in source order
+func PatternNames(pattern Pattern) []*Identifier {
+	var names []*Identifier
+
+	switch pattern := pattern.(type) {
+	case *Identifier:
+		names = append(names, pattern)
+	case *ArrayPattern:
+		for _, elem := range pattern.Elements {
+			names = append(names, PatternNames(elem)...)
+		}
+		if pattern.Rest != nil {
+			names = append(names, pattern.Rest)
+		}
+	case *HashPattern:
+		for _, value := range pattern.Values {
+			names = append(names, PatternNames(value)...)
+		}
+		if pattern.Rest != nil {
+			names = append(names, pattern.Rest)
+		}
+	case *ConstructorPattern:
+		for _, elem := range pattern.Elements {
+			names = append(names, PatternNames(elem)...)
+		}
+		for _, value := range pattern.Values {
+			names = append(names, PatternNames(value)...)
+		}
+	}
+
+	return names
+}
@@ -2,15 +2,17 @@ package ast
 
 import (
 	"bytes"
+	"strings"
 
 	"github.com/caelondev/monkey/src/token"
 )
 
 // ---------------- VarStatement ----------------
 type VarStatement struct {
-	Token token.Token   // LET Token
-	Names []*Identifier // All names will receive same value
-	Value Expression
+	Token    token.Token // LET Token
+	Targets  []Pattern   // With more than one, the value is unpacked across them ---
+	Value    Expression
+	Constant bool // Declared with const, the names can't be reassigned ---
 }
 
 func (vs *VarStatement) GetLine() uint {
@@ -26,13 +28,13 @@ func (vs *VarStatement) String() string {
 	out.WriteString(vs.Token.Literal)
 	out.WriteString(" ")
 
-	if len(vs.Names) > 1 {
-		for i := 0; i < len(vs.Names)-1; i++ {
-			out.WriteString(vs.Names[i].String())
+	if len(vs.Targets) > 1 {
+		for i := 0; i < len(vs.Targets)-1; i++ {
+			out.WriteString(vs.Targets[i].String())
 			out.WriteString(", ")
 		}
 	}
-	out.WriteString(vs.Names[len(vs.Names)-1].String())
+	out.WriteString(vs.Targets[len(vs.Targets)-1].String())
 	out.WriteString(" = ")
 	out.WriteString(vs.Value.String())
 
@@ -98,6 +100,7 @@ func (es *ExpressionStatement) TokenLiteral() string {
 type BlockStatement struct {
 	Token      token.Token
 	Statements []Statement
+	EndToken   token.Token // Closing }, tells where trailing comments stop ---
 }
 
 func (bs *BlockStatement) GetLine() uint {
@@ -158,10 +161,89 @@ func (is *IfStatement) TokenLiteral() string {
 	return is.Token.Literal
 }
 
+// ---------------- SwitchStatement ----------------
+type SwitchStatement struct {
+	Token    token.Token
+	Subject  Expression
+	Cases    []*SwitchCase // In source order, default among them ---
+	EndToken token.Token   // The closing }, comments before it stay inside ---
+}
+
+func (ss *SwitchStatement) GetLine() uint {
+	return ss.Token.Line
+}
+func (ss *SwitchStatement) GetColumn() uint {
+	return ss.Token.Column
+}
+
+func (ss *SwitchStatement) statementNode() {}
+func (ss *SwitchStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ss.TokenLiteral())
+	out.WriteString(" (")
+	out.WriteString(ss.Subject.String())
+	out.WriteString(") {\n")
+
+	for _, c := range ss.Cases {
+		out.WriteString(c.String())
+	}
+
+	out.WriteString("}")
+
+	return out.String()
+}
+func (ss *SwitchStatement) TokenLiteral() string {
+	return ss.Token.Literal
+}
+
+// ---------------- SwitchCase ----------------
+type SwitchCase struct {
+	Token  token.Token // case or default ---
+	Values []Expression
+	Body   *BlockStatement // Shares the enclosing scope, like an if body ---
+}
+
+func (sc *SwitchCase) GetLine() uint {
+	return sc.Token.Line
+}
+func (sc *SwitchCase) GetColumn() uint {
+	return sc.Token.Column
+}
+
+// IsDefault tells whether the case runs when no other one matches
+func (sc *SwitchCase) IsDefault() bool {
+	return sc.Token.Type == token.DEFAULT
+}
+
+func (sc *SwitchCase) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(sc.TokenLiteral())
+
+	if !sc.IsDefault() {
+		values := make([]string, len(sc.Values))
+		for i, value := range sc.Values {
+			values[i] = value.String()
+		}
+
+		out.WriteString(" ")
+		out.WriteString(strings.Join(values, ", "))
+	}
+
+	out.WriteString(":\n")
+	out.WriteString(sc.Body.String())
+
+	return out.String()
+}
+func (sc *SwitchCase) TokenLiteral() string {
+	return sc.Token.Literal
+}
+
 // ---------------- BatchAssignmentStatement ----------------
 type BatchAssignmentStatement struct {
 	Token     token.Token
-	Assignees []*Identifier
+	Assignees []Pattern // With more than one, the value is unpacked across them ---
 	NewValue  Expression
 }
 
@@ -177,6 +259,7 @@ func (ba *BatchAssignmentStatement) String() string {
 	var out bytes.Buffer
 
 	out.WriteString(ba.Token.Literal)
+	out.WriteString(" ")
 
 	for i := range len(ba.Assignees) - 1 {
 		assignee := ba.Assignees[i]
@@ -199,8 +282,9 @@ func (ba *BatchAssignmentStatement) TokenLiteral() string {
 type FunctionDeclarationStatement struct {
 	Token      token.Token
 	Name       *Identifier
-	Parameters []*Identifier
+	Parameters []*Parameter
 	Body       *BlockStatement
+	Slots      []string // Name of every resolved slot in the body's environment ---
 }
 
 func (bs *FunctionDeclarationStatement) GetLine() uint {
@@ -227,8 +311,8 @@ func (ba *FunctionDeclarationStatement) String() string {
 			out.WriteString(", ")
 		}
 
-	param := ba.Parameters[len(ba.Parameters)-1]
-	out.WriteString(param.String())
+		param := ba.Parameters[len(ba.Parameters)-1]
+		out.WriteString(param.String())
 	}
 
 	out.WriteString(") {\n")
@@ -246,3 +330,115 @@ func (ba *FunctionDeclarationStatement) String() string {
 func (ba *FunctionDeclarationStatement) TokenLiteral() string {
 	return ba.Token.Literal
 }
+
+// ---------------- StructStatement ----------------
+type StructStatement struct {
+	Token    token.Token
+	Name     *Identifier
+	Fields   []*Parameter // Plain names, a default runs for every instance ---
+	Methods  []*FunctionDeclarationStatement
+	EndToken token.Token // The closing }, comments before it stay inside ---
+}
+
+func (ss *StructStatement) GetLine() uint {
+	return ss.Token.Line
+}
+func (ss *StructStatement) GetColumn() uint {
+	return ss.Token.Column
+}
+
+func (ss *StructStatement) statementNode() {}
+func (ss *StructStatement) String() string {
+	var out bytes.Buffer
+
+	out.WriteString(ss.TokenLiteral())
+	out.WriteString(" ")
+	out.WriteString(ss.Name.Value)
+	out.WriteString(" {\n")
+
+	if len(ss.Fields) != 0 {
+		fields := make([]string, len(ss.Fields))
+		for i, field := range ss.Fields {
+			fields[i] = field.String()
+		}
+
+		out.WriteString("\t")
+		out.WriteString(strings.Join(fields, ", "))
+		out.WriteString(";\n")
+	}
+
+	for _, method := range ss.Methods {
+		out.WriteString("\t")
+		out.WriteString(method.String())
+	}
+
+	out.WriteString("}\n")
+
+	return out.String()
+}
+func (ss *StructStatement) TokenLiteral() string {
+	return ss.Token.Literal
+}
+
+// ---------------- EnumStatement ----------------
+type EnumStatement struct {
+	Token    token.Token
+	Name     *Identifier
+	Variants []*EnumVariant
+	EndToken token.Token // The closing }, comments before it stay inside ---
+}
+
+func (es *EnumStatement) GetLine() uint {
+	return es.Token.Line
+}
+func (es *EnumStatement) GetColumn() uint {
+	return es.Token.Column
+}
+
+func (es *EnumStatement) statementNode() {}
+func (es *EnumStatement) String() string {
+	variants := make([]string, len(es.Variants))
+	for i, variant := range es.Variants {
+		variants[i] = variant.String()
+	}
+
+	return es.TokenLiteral() + " " + es.Name.Value + " { " + strings.Join(variants, ", ") + " }"
+}
+func (es *EnumStatement) TokenLiteral() string {
+	return es.Token.Literal
+}
+
+// ---------------- EnumVariant ----------------
+type EnumVariant struct {
+	Name   *Identifier
+	Fields []*Identifier // Values the variant carries, none for a plain one ---
+}
+
+func (ev *EnumVariant) GetLine() uint {
+	return ev.Name.GetLine()
+}
+func (ev *EnumVariant) GetColumn() uint {
+	return ev.Name.GetColumn()
+}
+
+func (ev *EnumVariant) String() string {
+	if len(ev.Fields) == 0 {
+		return ev.Name.Value
+	}
+
+	fields := make([]string, len(ev.Fields))
+	for i, field := range ev.Fields {
+		fields[i] = field.Value
+	}
+
+	return ev.Name.Value + "(" + strings.Join(fields, ", ") + ")"
+}
+func (ev *EnumVariant) TokenLiteral() string {
+	return ev.Name.TokenLiteral()
+}
+
+// IsImplicitNil spots the nil the parser makes up for `var a;`
+func IsImplicitNil(node Expression) bool {
+	lit, ok := node.(*NilLiteral)
+	return ok && lit.Token.Type == token.SEMICOLON
+}
@@ -0,0 +1,173 @@
+package ast
+
+// Inspect walks the tree depth-first, calling fn for every node. Returning
+// false from fn skips that node's children
+func Inspect(node Node, fn func(Node) bool) {
+	if isNilNode(node) || !fn(node) {
+		return
+	}
+
+	for _, child := range children(node) {
+		Inspect(child, fn)
+	}
+}
+
+func children(node Node) []Node {
+	var nodes []Node
+
+	add := func(children ...Node) {
+		for _, child := range children {
+			if !isNilNode(child) {
+				nodes = append(nodes, child)
+			}
+		}
+	}
+
+	switch node := node.(type) {
+	case *Program:
+		for _, stmt := range node.Statements {
+			add(stmt)
+		}
+	case *BlockStatement:
+		for _, stmt := range node.Statements {
+			add(stmt)
+		}
+	case *VarStatement:
+		for _, target := range node.Targets {
+			add(target)
+		}
+		add(node.Value)
+	case *ReturnStatement:
+		add(node.ReturnValue)
+	case *ExpressionStatement:
+		add(node.Expression)
+	case *IfStatement:
+		add(node.Condition, node.Consequence, node.Alternative)
+	case *SwitchStatement:
+		add(node.Subject)
+		for _, c := range node.Cases {
+			add(c)
+		}
+	case *SwitchCase:
+		for _, value := range node.Values {
+			add(value)
+		}
+		add(node.Body)
+	case *BatchAssignmentStatement:
+		for _, assignee := range node.Assignees {
+			add(assignee)
+		}
+		add(node.NewValue)
+	case *FunctionDeclarationStatement:
+		add(node.Name)
+		for _, param := range node.Parameters {
+			add(param)
+		}
+		add(node.Body)
+	case *StructStatement:
+		add(node.Name)
+		for _, field := range node.Fields {
+			add(field)
+		}
+		for _, method := range node.Methods {
+			add(method)
+		}
+	case *EnumStatement:
+		add(node.Name)
+		for _, variant := range node.Variants {
+			add(variant)
+		}
+	case *EnumVariant:
+		add(node.Name)
+		for _, field := range node.Fields {
+			add(field)
+		}
+	case *MatchExpression:
+		add(node.Subject)
+		for _, arm := range node.Arms {
+			add(arm)
+		}
+	case *MatchArm:
+		add(node.Pattern, node.Guard, node.Body)
+	case *UnaryExpression:
+		add(node.Right)
+	case *BinaryExpression:
+		add(node.Left, node.Right)
+	case *TernaryExpression:
+		add(node.Consequence, node.Condition, node.Alternative)
+	case *FunctionLiteral:
+		for _, param := range node.Parameters {
+			add(param)
+		}
+		add(node.Body)
+	case *PipeExpression:
+		add(node.Call)
+	case *CallExpression:
+		add(node.Function)
+		for _, arg := range node.Arguments {
+			add(arg)
+		}
+	case *AssignmentExpression:
+		add(node.Assignee, node.NewValue)
+	case *ArrayLiteral:
+		for _, elem := range node.Elements {
+			add(elem)
+		}
+	case *IndexExpression:
+		add(node.Target, node.Index)
+	case *MemberExpression:
+		add(node.Target)
+	case *SliceExpression:
+		add(node.Target, node.Start, node.End, node.Step)
+	case *Parameter:
+		add(node.Target, node.Default)
+	case *ArrayPattern:
+		for _, elem := range node.Elements {
+			add(elem)
+		}
+		add(node.Rest)
+	case *HashPattern:
+		// Keys are names in the value, not variables ---
+		for _, value := range node.Values {
+			add(value)
+		}
+		add(node.Rest)
+	case *LiteralPattern:
+		add(node.Value)
+	case *ConstructorPattern:
+		add(node.Type)
+		for _, elem := range node.Elements {
+			add(elem)
+		}
+		// Keys are field names, not variables ---
+		for _, value := range node.Values {
+			add(value)
+		}
+	case *SpreadExpression:
+		add(node.Value)
+	case *NamedArgument:
+		add(node.Value)
+	case *UpdateExpression:
+		add(node.Target)
+	case *HashLiteral:
+		for i, key := range node.Keys {
+			add(key, node.Values[i])
+		}
+	}
+
+	return nodes
+}
+
+// isNilNode also catches typed nils, like a nil *BlockStatement in a Node
+func isNilNode(node Node) bool {
+	switch node := node.(type) {
+	case nil:
+		return true
+	case *BlockStatement:
+		return node == nil
+	case *Identifier:
+		return node == nil
+	}
+
+	return false
+}
@@ -0,0 +1,182 @@
+package debugger
+
+import (
+	"sync"
+
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/object"
+)
+
+type stepMode int
+
+const (
+	stepContinue stepMode = iota
+	stepIn
+	stepOver
+	stepOut
+)
+
+type frame struct {
+	name   string
+	env    *object.Environment
+	line   uint
+	column uint
+}
+
+// Debugger implements evaluation.Tracer, the evaluation goroutine blocks
+// inside BeforeStatement while the client inspects the paused program
+type Debugger struct {
+	transport *transport
+
+	mutex          sync.Mutex
+	breakpoints    map[uint]bool
+	pauseRequested bool
+	stopOnEntry    bool
+	paused         bool
+
+	frames    []*frame
+	mode      stepMode
+	stepDepth int
+	stepLine  uint
+	resume    chan stepMode
+
+	// variablesReference -> *object.Environment, *object.Array or *object.Hash ---
+	// Only valid while paused, every stop starts a fresh table ---
+	handles []any
+}
+
+func newDebugger(t *transport) *Debugger {
+	return &Debugger{
+		transport:   t,
+		breakpoints: make(map[uint]bool),
+		resume:      make(chan stepMode),
+	}
+}
+
+func (d *Debugger) BeforeStatement(node ast.Statement, env *object.Environment) {
+	top := d.frames[len(d.frames)-1]
+	previousLine := top.line
+
+	top.line = node.GetLine()
+	top.column = node.GetColumn()
+	top.env = env
+
+	if reason := d.stopReason(top.line, previousLine); reason != "" {
+		d.stop(reason)
+	}
+}
+
+func (d *Debugger) EnterFunction(fn *object.Function, callNode *ast.CallExpression, env *object.Environment) {
+	name := "<anonymous>"
+	if fn.Name != nil {
+		name = fn.Name.Value
+	}
+
+	d.frames = append(d.frames, &frame{name: name, env: env})
+}
+
+func (d *Debugger) ExitFunction(fn *object.Function) {
+	d.frames = d.frames[:len(d.frames)-1]
+}
+
+func (d *Debugger) stopReason(line, previousLine uint) string {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	depth := len(d.frames)
+
+	switch {
+	case d.stopOnEntry:
+		d.stopOnEntry = false
+		return "entry"
+	case d.pauseRequested:
+		d.pauseRequested = false
+		return "pause"
+	case d.breakpoints[line] && line != previousLine:
+		return "breakpoint"
+	case d.mode == stepIn && (depth != d.stepDepth || line != d.stepLine):
+		return "step"
+	case d.mode == stepOver && (depth < d.stepDepth || (depth == d.stepDepth && line != d.stepLine)):
+		return "step"
+	case d.mode == stepOut && depth < d.stepDepth:
+		return "step"
+	}
+
+	return ""
+}
+
+func (d *Debugger) stop(reason string) {
+	d.mutex.Lock()
+	d.paused = true
+	d.handles = nil
+	d.mutex.Unlock()
+
+	d.transport.sendEvent("stopped", map[string]any{
+		"reason":            reason,
+		"threadId":          mainThreadId,
+		"allThreadsStopped": true,
+	})
+
+	mode := <-d.resume
+
+	d.mutex.Lock()
+	d.paused = false
+	d.mode = mode
+	d.stepDepth = len(d.frames)
+	d.stepLine = d.frames[len(d.frames)-1].line
+	d.mutex.Unlock()
+}
+
+// continueWith resumes a paused program, it reports false if nothing was paused
+func (d *Debugger) continueWith(mode stepMode) bool {
+	d.mutex.Lock()
+	paused := d.paused
+	d.mutex.Unlock()
+
+	if !paused {
+		return false
+	}
+
+	d.resume <- mode
+	return true
+}
+
+func (d *Debugger) isPaused() bool {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	return d.paused
+}
+
+func (d *Debugger) setBreakpoints(lines []uint) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.breakpoints = make(map[uint]bool)
+	for _, line := range lines {
+		d.breakpoints[line] = true
+	}
+}
+
+func (d *Debugger) requestPause() {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+	d.pauseRequested = true
+}
+
+func (d *Debugger) newHandle(value any) int {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	d.handles = append(d.handles, value)
+	return len(d.handles) // References start at 1, 0 means "not expandable" ---
+}
+
+func (d *Debugger) handle(reference int) (any, bool) {
+	d.mutex.Lock()
+	defer d.mutex.Unlock()
+
+	if reference < 1 || reference > len(d.handles) {
+		return nil, false
+	}
+	return d.handles[reference-1], true
+}
@@ -0,0 +1,154 @@
+package debugger
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// NOTE: Only the slice of the Debug Adapter Protocol that this debugger ---
+// actually answers is modelled here, see the specification for the rest ---
+// https://microsoft.github.io/debug-adapter-protocol/specification ---
+
+type message struct {
+	Seq  int    `json:"seq"`
+	Type string `json:"type"`
+}
+
+type request struct {
+	message
+	Command   string          `json:"command"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+type response struct {
+	message
+	RequestSeq int    `json:"request_seq"`
+	Success    bool   `json:"success"`
+	Command    string `json:"command"`
+	Message    string `json:"message,omitempty"`
+	Body       any    `json:"body,omitempty"`
+}
+
+type event struct {
+	message
+	Event string `json:"event"`
+	Body  any    `json:"body,omitempty"`
+}
+
+type source struct {
+	Name string `json:"name,omitempty"`
+	Path string `json:"path,omitempty"`
+}
+
+type stackFrame struct {
+	Id     int    `json:"id"`
+	Name   string `json:"name"`
+	Source source `json:"source"`
+	Line   uint   `json:"line"`
+	Column uint   `json:"column"`
+}
+
+type scope struct {
+	Name               string `json:"name"`
+	VariablesReference int    `json:"variablesReference"`
+	Expensive          bool   `json:"expensive"`
+}
+
+type variable struct {
+	Name               string `json:"name"`
+	Value              string `json:"value"`
+	Type               string `json:"type,omitempty"`
+	VariablesReference int    `json:"variablesReference"`
+}
+
+type breakpoint struct {
+	Verified bool `json:"verified"`
+	Line     uint `json:"line"`
+}
+
+// ---------------- Transport ----------------
+// Every message is a JSON body preceded by a Content-Length header
+
+type transport struct {
+	reader *bufio.Reader
+	writer io.Writer
+	mutex  sync.Mutex // Events are sent from the evaluation goroutine too ---
+	seq    int
+}
+
+func newTransport(in io.Reader, out io.Writer) *transport {
+	return &transport{reader: bufio.NewReader(in), writer: out}
+}
+
+func (t *transport) readRequest() (*request, error) {
+	headers, err := textproto.NewReader(t.reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %w", err)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(t.reader, body); err != nil {
+		return nil, err
+	}
+
+	req := &request{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (t *transport) respond(req *request, body any) {
+	t.send(&response{
+		message:    message{Type: "response"},
+		RequestSeq: req.Seq,
+		Success:    true,
+		Command:    req.Command,
+		Body:       body,
+	})
+}
+
+func (t *transport) respondError(req *request, format string, a ...any) {
+	t.send(&response{
+		message:    message{Type: "response"},
+		RequestSeq: req.Seq,
+		Success:    false,
+		Command:    req.Command,
+		Message:    fmt.Sprintf(format, a...),
+	})
+}
+
+func (t *transport) sendEvent(name string, body any) {
+	t.send(&event{message: message{Type: "event"}, Event: name, Body: body})
+}
+
+func (t *transport) send(msg any) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	t.seq++
+	switch msg := msg.(type) {
+	case *response:
+		msg.Seq = t.seq
+	case *event:
+		msg.Seq = t.seq
+	}
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(t.writer, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
@@ -0,0 +1,394 @@
+package debugger
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/caelondev/monkey/src/analysis"
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/evaluation"
+	"github.com/caelondev/monkey/src/lexer"
+	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/parser"
+)
+
+const mainThreadId = 1
+
+type server struct {
+	transport *transport
+	debugger  *Debugger
+
+	path    string
+	program *ast.Program
+}
+
+// Serve speaks the Debug Adapter Protocol over the given streams until the
+// client disconnects. The script's own output is sent as "output" events
+func Serve(in io.Reader, out io.Writer) error {
+	t := newTransport(in, out)
+	s := &server{transport: t, debugger: newDebugger(t)}
+
+	for {
+		req, err := t.readRequest()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if done := s.handle(req); done {
+			return nil
+		}
+	}
+}
+
+func (s *server) handle(req *request) bool {
+	switch req.Command {
+	case "initialize":
+		s.transport.respond(req, map[string]any{
+			"supportsConfigurationDoneRequest": true,
+			"supportsEvaluateForHovers":        true,
+		})
+		s.transport.sendEvent("initialized", nil)
+
+	case "launch":
+		s.launch(req)
+
+	case "setBreakpoints":
+		s.setBreakpoints(req)
+
+	case "configurationDone":
+		s.transport.respond(req, nil)
+		go s.run()
+
+	case "threads":
+		s.transport.respond(req, map[string]any{
+			"threads": []map[string]any{{"id": mainThreadId, "name": "main"}},
+		})
+
+	case "stackTrace":
+		s.stackTrace(req)
+
+	case "scopes":
+		s.scopes(req)
+
+	case "variables":
+		s.variables(req)
+
+	case "evaluate":
+		s.evaluate(req)
+
+	case "continue":
+		s.resume(req, stepContinue)
+	case "next":
+		s.resume(req, stepOver)
+	case "stepIn":
+		s.resume(req, stepIn)
+	case "stepOut":
+		s.resume(req, stepOut)
+
+	case "pause":
+		s.debugger.requestPause()
+		s.transport.respond(req, nil)
+
+	case "disconnect", "terminate":
+		s.transport.respond(req, nil)
+		return true
+
+	default:
+		s.transport.respondError(req, "Unsupported request '%s'", req.Command)
+	}
+
+	return false
+}
+
+func (s *server) launch(req *request) {
+	var args struct {
+		Program     string `json:"program"`
+		StopOnEntry bool   `json:"stopOnEntry"`
+	}
+
+	if err := json.Unmarshal(req.Arguments, &args); err != nil || args.Program == "" {
+		s.transport.respondError(req, "Launch requires a 'program' to debug")
+		return
+	}
+
+	source, err := os.ReadFile(args.Program)
+	if err != nil {
+		s.transport.respondError(req, "Cannot read '%s': %s", args.Program, err.Error())
+		return
+	}
+
+	p := parser.New(lexer.New(string(source)))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		s.transport.respondError(req, "An error occured whilst parsing:\n%s", strings.Join(p.Errors(), "\n"))
+		return
+	}
+
+	if undefined := analysis.Analyze(program).Undefined; len(undefined) != 0 {
+		ident := undefined[0]
+		s.transport.respondError(req, "[Ln %d:%d] Cannot resolve variable '%s'", ident.GetLine(), ident.GetColumn(), ident.Value)
+		return
+	}
+
+	s.path = args.Program
+	s.program = program
+	s.debugger.stopOnEntry = args.StopOnEntry
+	s.transport.respond(req, nil)
+}
+
+func (s *server) setBreakpoints(req *request) {
+	var args struct {
+		Breakpoints []struct {
+			Line uint `json:"line"`
+		} `json:"breakpoints"`
+	}
+	json.Unmarshal(req.Arguments, &args)
+
+	lines := make([]uint, 0, len(args.Breakpoints))
+	verified := make([]breakpoint, 0, len(args.Breakpoints))
+
+	for _, bp := range args.Breakpoints {
+		lines = append(lines, bp.Line)
+		verified = append(verified, breakpoint{Verified: true, Line: bp.Line})
+	}
+
+	s.debugger.setBreakpoints(lines)
+	s.transport.respond(req, map[string]any{"breakpoints": verified})
+}
+
+func (s *server) run() {
+	if s.program == nil {
+		s.transport.sendEvent("terminated", nil)
+		return
+	}
+
+	evaluator := evaluation.New()
+	env := evaluator.NewGlobalEnvironment()
+	s.debugger.frames = []*frame{{name: "<main>", env: env}}
+
+	evaluator.SetIO(strings.NewReader(""), &outputWriter{s.transport, "stdout"})
+	evaluator.SetTracer(s.debugger)
+
+	exitCode := 0
+	result := evaluator.Evaluate(s.program, env)
+
+	if err, ok := result.(*object.Error); ok {
+		exitCode = 1
+		s.transport.sendEvent("output", map[string]any{
+			"category": "stderr",
+			"output":   fmt.Sprintf("[Ln %d:%d] Runtime::Error -> %s\n", err.Line, err.Column, err.Message),
+		})
+	}
+
+	s.transport.sendEvent("exited", map[string]any{"exitCode": exitCode})
+	s.transport.sendEvent("terminated", nil)
+}
+
+func (s *server) resume(req *request, mode stepMode) {
+	s.transport.respond(req, map[string]any{"allThreadsContinued": true})
+	s.debugger.continueWith(mode)
+}
+
+func (s *server) stackTrace(req *request) {
+	if !s.debugger.isPaused() {
+		s.transport.respondError(req, "The program is not paused")
+		return
+	}
+
+	frames := s.debugger.frames
+	stackFrames := make([]stackFrame, 0, len(frames))
+	src := source{Name: filepath.Base(s.path), Path: s.path}
+
+	// Innermost frame first ---
+	for i := len(frames) - 1; i >= 0; i-- {
+		stackFrames = append(stackFrames, stackFrame{
+			Id:     i,
+			Name:   frames[i].name,
+			Source: src,
+			Line:   frames[i].line,
+			Column: frames[i].column,
+		})
+	}
+
+	s.transport.respond(req, map[string]any{"stackFrames": stackFrames, "totalFrames": len(stackFrames)})
+}
+
+func (s *server) scopes(req *request) {
+	var args struct {
+		FrameId int `json:"frameId"`
+	}
+	json.Unmarshal(req.Arguments, &args)
+
+	if !s.debugger.isPaused() {
+		s.transport.respondError(req, "The program is not paused")
+		return
+	}
+
+	f, ok := s.frame(args.FrameId)
+	if !ok {
+		s.transport.respondError(req, "Unknown frame %d", args.FrameId)
+		return
+	}
+
+	scopes := make([]scope, 0)
+
+	for env := f.env; env != nil; env = env.GetOuter() {
+		name := "Closure"
+		switch {
+		case env.GetOuter() == nil:
+			name = "Builtins"
+		case env.GetOuter().GetOuter() == nil:
+			name = "Globals"
+		case env == f.env:
+			name = "Locals"
+		}
+
+		scopes = append(scopes, scope{Name: name, VariablesReference: s.debugger.newHandle(env)})
+	}
+
+	s.transport.respond(req, map[string]any{"scopes": scopes})
+}
+
+func (s *server) variables(req *request) {
+	var args struct {
+		VariablesReference int `json:"variablesReference"`
+	}
+	json.Unmarshal(req.Arguments, &args)
+
+	if !s.debugger.isPaused() {
+		s.transport.respondError(req, "The program is not paused")
+		return
+	}
+
+	value, ok := s.debugger.handle(args.VariablesReference)
+	if !ok {
+		s.transport.respondError(req, "Unknown variables reference %d", args.VariablesReference)
+		return
+	}
+
+	variables := make([]variable, 0)
+
+	switch value := value.(type) {
+	case *object.Environment:
+		for _, name := range value.Names() {
+			obj, _ := value.Get(name)
+			variables = append(variables, s.toVariable(name, obj))
+		}
+	case *object.Array:
+		for i, elem := range value.Elements {
+			variables = append(variables, s.toVariable(fmt.Sprintf("[%d]", i), elem))
+		}
+	case *object.Hash:
+		value.Each(func(pair *object.HashPair) {
+			variables = append(variables, s.toVariable("["+pair.Key.Inspect()+"]", pair.Value))
+		})
+	case *object.Instance:
+		for i, field := range value.Struct.Fields {
+			variables = append(variables, s.toVariable(field.Ident().Value, value.Values[i]))
+		}
+	case *object.Variant:
+		for i, field := range value.Decl.Fields {
+			variables = append(variables, s.toVariable(field.Value, value.Values[i]))
+		}
+	}
+
+	s.transport.respond(req, map[string]any{"variables": variables})
+}
+
+func (s *server) evaluate(req *request) {
+	var args struct {
+		Expression string `json:"expression"`
+		FrameId    *int   `json:"frameId"`
+	}
+	json.Unmarshal(req.Arguments, &args)
+
+	if !s.debugger.isPaused() {
+		s.transport.respondError(req, "Expressions can only be evaluated while paused")
+		return
+	}
+
+	frameId := len(s.debugger.frames) - 1
+	if args.FrameId != nil {
+		frameId = *args.FrameId
+	}
+
+	f, ok := s.frame(frameId)
+	if !ok {
+		s.transport.respondError(req, "Unknown frame %d", frameId)
+		return
+	}
+
+	p := parser.New(lexer.New(args.Expression))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		s.transport.respondError(req, "%s", strings.Join(p.Errors(), "\n"))
+		return
+	}
+
+	// A separate evaluator without the tracer, so watches never hit breakpoints ---
+	evaluator := evaluation.New()
+	evaluator.SetIO(strings.NewReader(""), &outputWriter{s.transport, "console"})
+	result := evaluator.Evaluate(program, f.env)
+
+	if result == nil {
+		result = object.NIL
+	}
+
+	if err, ok := result.(*object.Error); ok {
+		s.transport.respondError(req, "%s", err.Message)
+		return
+	}
+
+	v := s.toVariable("", result)
+	s.transport.respond(req, map[string]any{
+		"result":             v.Value,
+		"type":               v.Type,
+		"variablesReference": v.VariablesReference,
+	})
+}
+
+func (s *server) frame(id int) (*frame, bool) {
+	if id < 0 || id >= len(s.debugger.frames) {
+		return nil, false
+	}
+	return s.debugger.frames[id], true
+}
+
+func (s *server) toVariable(name string, obj object.Object) variable {
+	v := variable{Name: name, Value: obj.Inspect(), Type: string(obj.Type())}
+
+	if arr, ok := obj.(*object.Array); ok && len(arr.Elements) != 0 {
+		v.VariablesReference = s.debugger.newHandle(arr)
+	}
+	if hash, ok := obj.(*object.Hash); ok && len(hash.Order) != 0 {
+		v.VariablesReference = s.debugger.newHandle(hash)
+	}
+	if instance, ok := obj.(*object.Instance); ok && len(instance.Values) != 0 {
+		v.VariablesReference = s.debugger.newHandle(instance)
+	}
+	if variant, ok := obj.(*object.Variant); ok && len(variant.Values) != 0 {
+		v.VariablesReference = s.debugger.newHandle(variant)
+	}
+
+	return v
+}
+
+// outputWriter forwards whatever the script prints to the client
+type outputWriter struct {
+	transport *transport
+	category  string
+}
+
+func (w *outputWriter) Write(p []byte) (int, error) {
+	w.transport.sendEvent("output", map[string]any{"category": w.category, "output": string(p)})
+	return len(p), nil
+}
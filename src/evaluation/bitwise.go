@@ -0,0 +1,119 @@
+package evaluation
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/token"
+)
+
+// NOTE: Bitwise operators work on whole numbers, as two's complement ---
+// of whatever width the value needs. Floats and decimals holding a ---
+// whole value act as that integer, a fraction is an error. Inf has ---
+// no bits, it goes through the inf-nan tables and gives NaN like NaN ---
+
+// Shifting further than this would only build a number nobody can use ---
+const maxShift = 1 << 20
+
+func isBitwiseOperator(op token.TokenType) bool {
+	switch op {
+	case token.AMPERSAND, token.PIPE, token.XOR, token.SHIFT_LEFT, token.SHIFT_RIGHT:
+		return true
+	}
+	return false
+}
+
+// wholeNumber gives the integer a number holds, false when it has a fraction
+// or isn't a number at all
+func wholeNumber(obj object.Object) (*big.Int, bool) {
+	switch obj := obj.(type) {
+	case *object.Integer, *object.BigInt:
+		return toBig(obj), true
+	case *object.Number:
+		if obj.Value != math.Trunc(obj.Value) {
+			return nil, false
+		}
+		value, _ := big.NewFloat(obj.Value).Int(nil)
+		return value, true
+	case *object.Decimal:
+		value := decimalRat(obj)
+		if !value.IsInt() {
+			return nil, false
+		}
+		return new(big.Int).Set(value.Num()), true
+	}
+
+	return nil, false
+}
+
+func integerOf(value *big.Int) object.Object {
+	if value.IsInt64() {
+		return &object.Integer{Value: value.Int64()}
+	}
+	return &object.BigInt{Value: value}
+}
+
+func (e *Evaluator) evaluateBitwiseExpression(node *ast.BinaryExpression, left, right object.Object) object.Object {
+	l, lok := wholeNumber(left)
+	r, rok := wholeNumber(right)
+
+	if !lok || !rok {
+		return e.throwErr(
+			node,
+			"This error occurs when a bitwise operator is used on something that isn't a whole number. Use // to drop the fraction first",
+			"Cannot perform `%v %v %v`, bitwise operators need whole numbers",
+			left.Inspect(),
+			node.Operator.Literal,
+			right.Inspect(),
+		)
+	}
+
+	switch node.Operator.Type {
+	case token.AMPERSAND:
+		return integerOf(new(big.Int).And(l, r))
+	case token.PIPE:
+		return integerOf(new(big.Int).Or(l, r))
+	case token.XOR:
+		return integerOf(new(big.Int).Xor(l, r))
+
+	case token.SHIFT_LEFT, token.SHIFT_RIGHT:
+		if r.Sign() < 0 || r.Cmp(big.NewInt(maxShift)) > 0 {
+			return e.throwErr(
+				node,
+				"This error occurs when a shift count is negative or absurdly large",
+				"Cannot shift by %v, the count must be between 0 and %d",
+				r,
+				maxShift,
+			)
+		}
+
+		// Rsh floors, so negative numbers keep their sign ---
+		if node.Operator.Type == token.SHIFT_LEFT {
+			return integerOf(new(big.Int).Lsh(l, uint(r.Uint64())))
+		}
+		return integerOf(new(big.Int).Rsh(l, uint(r.Uint64())))
+	}
+
+	return e.unknownIntegerOperator(node)
+}
+
+func (e *Evaluator) evaluateBitwiseNot(node *ast.UnaryExpression, right object.Object) object.Object {
+	switch right.(type) {
+	case *object.NaN, *object.Infinity:
+		return object.NAN
+	}
+
+	value, ok := wholeNumber(right)
+	if !ok {
+		return e.throwErr(
+			node,
+			"This error occurs when ~ is used on something that isn't a whole number",
+			"Cannot perform `~%v`, bitwise operators need whole numbers",
+			right.Inspect(),
+		)
+	}
+
+	return integerOf(new(big.Int).Not(value))
+}
@@ -0,0 +1,56 @@
+package evaluation
+
+import (
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/object"
+)
+
+// NOTE: a?.b, a?[i] and f?.() are nil when what's on their left is nil. ---
+// The rest of the chain is skipped with it, so a?.b.c and s?.upper() ---
+// don't go on to fail on that nil. Links read their left side through ---
+// chainTarget, which keeps the skip, and Evaluate settles it into nil ---
+// where the chain ends ---
+
+// skippedChain is what a chain holds once a ? link met nil
+type skippedChain struct{}
+
+func (s *skippedChain) Type() object.ObjectType {
+	return object.NIL_OBJECT
+}
+func (s *skippedChain) Inspect() string {
+	return "nil"
+}
+
+var skipped = &skippedChain{}
+
+// chainTarget evaluates the left side of a member, index, slice or call,
+// leaving a skipped chain as it is
+func (e *Evaluator) chainTarget(node ast.Expression, env *object.Environment) object.Object {
+	switch node := node.(type) {
+	case *ast.MemberExpression:
+		return e.evaluateMemberExpression(node, env)
+	case *ast.IndexExpression:
+		return e.evaluateIndexExpression(node, env)
+	case *ast.SliceExpression:
+		return e.evaluateSliceExpression(node, env)
+	case *ast.CallExpression:
+		return e.evaluateCallExpression(node, env)
+	}
+
+	return e.Evaluate(node, env)
+}
+
+// skipsLink tells whether a link has nothing left to do, because the chain
+// was skipped before it or it's a ? link on nil
+func skipsLink(optional bool, target object.Object) bool {
+	return target == skipped || (optional && target == object.NIL)
+}
+
+// settle ends a chain, one that was skipped is nil
+func settle(obj object.Object) object.Object {
+	if obj == skipped {
+		return object.NIL
+	}
+
+	return obj
+}
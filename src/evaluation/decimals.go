@@ -0,0 +1,201 @@
+package evaluation
+
+import (
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/token"
+)
+
+// NOTE: Decimals win against every other number: integers and floats ---
+// meeting one are turned into decimals (a float by its shortest ---
+// spelling, so 0.1 stays 0.1). Only Inf and NaN can't be decimals, ---
+// there the decimal becomes a float instead ---
+
+// Places kept by a division whose result never terminates, like 1d / 3d ---
+const decimalDivisionPlaces = 20
+
+var bigTen = big.NewInt(10)
+
+func toDecimal(obj object.Object) (*object.Decimal, bool) {
+	switch obj := obj.(type) {
+	case *object.Decimal:
+		return obj, true
+	case *object.Integer, *object.BigInt:
+		return &object.Decimal{Unscaled: toBig(obj), Scale: 0}, true
+	case *object.Number:
+		text := strconv.FormatFloat(obj.Value, 'f', -1, 64)
+		whole, fraction, _ := strings.Cut(text, ".")
+		unscaled, ok := new(big.Int).SetString(whole+fraction, 10)
+		return &object.Decimal{Unscaled: unscaled, Scale: len(fraction)}, ok
+	}
+
+	return nil, false
+}
+
+func decimalRat(d *object.Decimal) *big.Rat {
+	return new(big.Rat).SetFrac(d.Unscaled, pow10(d.Scale))
+}
+
+func pow10(n int) *big.Int {
+	return new(big.Int).Exp(bigTen, big.NewInt(int64(n)), nil)
+}
+
+// rescale returns the unscaled value of d at a larger scale
+func rescale(d *object.Decimal, scale int) *big.Int {
+	return new(big.Int).Mul(d.Unscaled, pow10(scale-d.Scale))
+}
+
+// decimalFromRat rounds half to even at the given scale
+func decimalFromRat(r *big.Rat, scale int) *object.Decimal {
+	numerator := new(big.Int).Mul(r.Num(), pow10(scale))
+	quotient, remainder := new(big.Int).QuoRem(numerator, r.Denom(), new(big.Int))
+
+	twice := new(big.Int).Abs(remainder)
+	twice.Lsh(twice, 1)
+
+	if cmp := twice.Cmp(r.Denom()); cmp > 0 || (cmp == 0 && quotient.Bit(0) == 1) {
+		if r.Sign() < 0 {
+			quotient.Sub(quotient, big.NewInt(1))
+		} else {
+			quotient.Add(quotient, big.NewInt(1))
+		}
+	}
+
+	return &object.Decimal{Unscaled: quotient, Scale: scale}
+}
+
+// terminatingScale is how many places a rational needs to be exact, or false
+// when its expansion never ends
+func terminatingScale(r *big.Rat) (int, bool) {
+	denominator := new(big.Int).Set(r.Denom())
+	twos, fives := 0, 0
+
+	for denominator.Bit(0) == 0 {
+		denominator.Rsh(denominator, 1)
+		twos++
+	}
+
+	five := big.NewInt(5)
+	for new(big.Int).Mod(denominator, five).Sign() == 0 {
+		denominator.Quo(denominator, five)
+		fives++
+	}
+
+	return max(twos, fives), denominator.Cmp(big.NewInt(1)) == 0
+}
+
+func (e *Evaluator) evaluateDecimalBinaryExpression(node *ast.BinaryExpression, l, r *object.Decimal) object.Object {
+	scale := max(l.Scale, r.Scale)
+
+	switch node.Operator.Type {
+	case token.PLUS:
+		return &object.Decimal{Unscaled: new(big.Int).Add(rescale(l, scale), rescale(r, scale)), Scale: scale}
+	case token.MINUS:
+		return &object.Decimal{Unscaled: new(big.Int).Sub(rescale(l, scale), rescale(r, scale)), Scale: scale}
+	case token.STAR:
+		return &object.Decimal{Unscaled: new(big.Int).Mul(l.Unscaled, r.Unscaled), Scale: l.Scale + r.Scale}
+
+	case token.SLASH:
+		if r.Unscaled.Sign() == 0 {
+			return e.decimalDivisionByZero(node)
+		}
+		return divideDecimals(l, r)
+
+	case token.FLOOR_DIV:
+		if r.Unscaled.Sign() == 0 {
+			return e.decimalDivisionByZero(node)
+		}
+		quotient := new(big.Rat).Quo(decimalRat(l), decimalRat(r))
+		return &object.Decimal{Unscaled: new(big.Int).Div(quotient.Num(), quotient.Denom()), Scale: 0}
+
+	case token.PERCENT:
+		if r.Unscaled.Sign() == 0 {
+			return e.decimalDivisionByZero(node)
+		}
+
+		divisor := rescale(r, scale)
+		remainder := new(big.Int).Rem(rescale(l, scale), divisor)
+		if remainder.Sign() != 0 && remainder.Sign() != divisor.Sign() {
+			remainder.Add(remainder, divisor)
+		}
+		return &object.Decimal{Unscaled: remainder, Scale: scale}
+
+	case token.CARET:
+		exponent := decimalRat(r)
+		if !exponent.IsInt() || !exponent.Num().IsInt64() {
+			// Fractional powers aren't exact anyway ---
+			return e.evaluateNumericBinaryExpression(node, &object.Number{Value: toFloat(l)}, &object.Number{Value: toFloat(r)})
+		}
+
+		n := exponent.Num().Int64()
+		if n < 0 && l.Unscaled.Sign() == 0 {
+			return e.decimalDivisionByZero(node)
+		}
+
+		// Both the digits and the scale grow with the power ---
+		if powerTooLarge(l.Unscaled, big.NewInt(abs(n))) || powerTooLarge(pow10(l.Scale), big.NewInt(abs(n))) {
+			return e.powerOverflow(node, big.NewInt(n))
+		}
+
+		power := &object.Decimal{
+			Unscaled: new(big.Int).Exp(l.Unscaled, big.NewInt(abs(n)), nil),
+			Scale:    l.Scale * int(abs(n)),
+		}
+		if n < 0 {
+			return divideDecimals(&object.Decimal{Unscaled: big.NewInt(1)}, power)
+		}
+		return power
+
+	case token.LESS:
+		return e.evaluateToObjectBoolean(decimalRat(l).Cmp(decimalRat(r)) < 0)
+	case token.GREATER:
+		return e.evaluateToObjectBoolean(decimalRat(l).Cmp(decimalRat(r)) > 0)
+	case token.LESS_EQUAL:
+		return e.evaluateToObjectBoolean(decimalRat(l).Cmp(decimalRat(r)) <= 0)
+	case token.GREATER_EQUAL:
+		return e.evaluateToObjectBoolean(decimalRat(l).Cmp(decimalRat(r)) >= 0)
+	case token.EQUAL:
+		return e.evaluateToObjectBoolean(decimalRat(l).Cmp(decimalRat(r)) == 0)
+	case token.NOT_EQUAL:
+		return e.evaluateToObjectBoolean(decimalRat(l).Cmp(decimalRat(r)) != 0)
+	}
+
+	return e.throwErr(
+		node,
+		"This error occurs when an unregistered binary operator is used.\nThis should only appear during language development.",
+		"Unknown decimal operator: '%v'",
+		node.Operator.Type,
+	)
+}
+
+// divideDecimals is exact when the quotient terminates, keeping at least the
+// operands' scale, and rounds to decimalDivisionPlaces otherwise
+func divideDecimals(l, r *object.Decimal) *object.Decimal {
+	quotient := new(big.Rat).Quo(decimalRat(l), decimalRat(r))
+	scale := max(l.Scale, r.Scale)
+
+	if exact, ok := terminatingScale(quotient); ok {
+		return decimalFromRat(quotient, max(scale, exact))
+	}
+
+	return decimalFromRat(quotient, max(scale, decimalDivisionPlaces))
+}
+
+func (e *Evaluator) decimalDivisionByZero(node *ast.BinaryExpression) object.Object {
+	return e.throwErr(
+		node,
+		"This error occurs when a decimal is divided by zero, decimals have no Infinity",
+		"Decimal division by zero",
+	)
+}
+
+func abs(n int64) int64 {
+	if n < 0 {
+		return -n
+	}
+	return n
+}
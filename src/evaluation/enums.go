@@ -0,0 +1,118 @@
+package evaluation
+
+import (
+	"slices"
+
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/token"
+)
+
+// NOTE: An enum is read like a namespace of its variants. A variant ---
+// without fields is a single value, so Color.Red is always the same ---
+// one, while a variant with fields reads as a constructor for them. ---
+// Two enum values are == when they're the same variant of the same ---
+// enum holding equal values ---
+
+func (e *Evaluator) evaluateEnumStatement(node *ast.EnumStatement, env *object.Environment) object.Object {
+	enum := &object.Enum{
+		Name:     node.Name.Value,
+		Variants: node.Variants,
+		Units:    make(map[string]*object.Variant),
+	}
+
+	for _, variant := range node.Variants {
+		if len(variant.Fields) == 0 {
+			enum.Units[variant.Name.Value] = &object.Variant{Enum: enum, Decl: variant}
+		}
+	}
+
+	declareVariable(node.Name, env, enum)
+	return enum
+}
+
+func (e *Evaluator) enumMember(node *ast.MemberExpression, enum *object.Enum) object.Object {
+	if unit, ok := enum.Units[node.Property]; ok {
+		return unit
+	}
+
+	decl := enum.Variant(node.Property)
+	if decl == nil {
+		return e.throwErr(
+			node,
+			"This error occurs when an enum declares no variant of that name",
+			"Enum '%s' has no variant '%s'",
+			enum.Name,
+			node.Property,
+		)
+	}
+
+	params := make([]string, len(decl.Fields))
+	for i, field := range decl.Fields {
+		params[i] = field.Value
+	}
+
+	return &object.NativeFunction{
+		Parameters: params,
+		Fn: func(call *ast.CallExpression, args []object.Object) object.Object {
+			if len(args) != len(decl.Fields) {
+				return e.throwErr(
+					call,
+					"This error occurs when a variant is given a different number of values than it declares",
+					"Variant '%s.%s' takes %d values, got %d",
+					enum.Name,
+					decl.Name.Value,
+					len(decl.Fields),
+					len(args),
+				)
+			}
+
+			return &object.Variant{Enum: enum, Decl: decl, Values: slices.Clone(args)}
+		},
+	}
+}
+
+// variantMember reads one of the values a variant carries
+func (e *Evaluator) variantMember(node *ast.MemberExpression, variant *object.Variant) object.Object {
+	for i, field := range variant.Decl.Fields {
+		if field.Value == node.Property {
+			return variant.Values[i]
+		}
+	}
+
+	return e.throwErr(
+		node,
+		"This error occurs when a variant declares no field of that name",
+		"'%s.%s' has no field '%s'",
+		variant.Enum.Name,
+		variant.Decl.Name.Value,
+		node.Property,
+	)
+}
+
+// evaluateVariantEquality answers == and != when either side is an enum
+// value, false when node is any other operator
+func (e *Evaluator) evaluateVariantEquality(node *ast.BinaryExpression, left, right object.Object) (object.Object, bool) {
+	if node.Operator.Type != token.EQUAL && node.Operator.Type != token.NOT_EQUAL {
+		return nil, false
+	}
+
+	equal := e.valuesEqual(comparison(node, token.EQUAL), left, right)
+	return e.evaluateToObjectBoolean(equal == (node.Operator.Type == token.EQUAL)), true
+}
+
+// variantsEqual compares the fields of two enum values the way a switch
+// compares its cases
+func (e *Evaluator) variantsEqual(equal *ast.BinaryExpression, left, right *object.Variant) bool {
+	if left.Enum != right.Enum || left.Decl != right.Decl {
+		return false
+	}
+
+	for i := range left.Values {
+		if !e.valuesEqual(equal, left.Values[i], right.Values[i]) {
+			return false
+		}
+	}
+
+	return true
+}
@@ -2,32 +2,85 @@
 package evaluation
 
 import (
+	"io"
+	"os"
+
 	"github.com/caelondev/monkey/src/ast"
 	"github.com/caelondev/monkey/src/object"
 )
 
+// Tracer observes the evaluation as it happens, the debugger uses it to
+// pause between statements and to keep track of the call stack
+type Tracer interface {
+	BeforeStatement(node ast.Statement, env *object.Environment)
+	EnterFunction(fn *object.Function, callNode *ast.CallExpression, env *object.Environment)
+	ExitFunction(fn *object.Function)
+}
+
 type Evaluator struct {
 	line   uint
 	column uint
+
+	stdin   io.Reader
+	stdout  io.Writer
+	tracer  Tracer
+	prelude *object.Environment
 }
 
 func New() Evaluator {
-	return Evaluator{}
+	return Evaluator{stdin: os.Stdin, stdout: os.Stdout}
 }
 
-func (e *Evaluator) Evaluate(node ast.Node, env *object.Environment) object.Object {
-	if env.GetOuter() == nil { // Global env
-		e.InitializeNativeFunctions(env)
+// SetIO redirects what the native functions read from and print to
+func (e *Evaluator) SetIO(stdin io.Reader, stdout io.Writer) {
+	e.stdin = stdin
+	e.stdout = stdout
+}
+
+func (e *Evaluator) SetTracer(tracer Tracer) {
+	e.tracer = tracer
+}
+
+// Prelude is the outermost scope and holds the native functions. It's only
+// built once, globals live in a scope it encloses so a user declaration
+// can shadow a native without replacing it
+func (e *Evaluator) Prelude() *object.Environment {
+	if e.prelude == nil {
+		e.prelude = object.NewEnvironment(nil)
+		e.InitializeNativeFunctions(e.prelude)
 	}
 
+	return e.prelude
+}
+
+// NewGlobalEnvironment makes an empty global scope on top of the prelude
+func (e *Evaluator) NewGlobalEnvironment() *object.Environment {
+	return object.NewEnvironment(e.Prelude())
+}
+
+func (e *Evaluator) Evaluate(node ast.Node, env *object.Environment) object.Object {
 	e.line = node.GetLine()
 	e.column = node.GetColumn()
 
+	if e.tracer != nil {
+		if stmt, ok := node.(ast.Statement); ok {
+			if _, isBlock := stmt.(*ast.BlockStatement); !isBlock {
+				e.tracer.BeforeStatement(stmt, env)
+			}
+		}
+	}
+
 	switch node := node.(type) {
 	case *ast.Program:
 		return e.evaluateProgram(node.Statements, env)
 	case *ast.NumberLiteral:
 		return &object.Number{Value: node.Value}
+	case *ast.IntegerLiteral:
+		return &object.Integer{Value: node.Value}
+	case *ast.BigIntLiteral:
+		return &object.BigInt{Value: node.Value}
+	case *ast.DecimalLiteral:
+		return &object.Decimal{Unscaled: node.Unscaled, Scale: node.Scale}
 	case *ast.StringLiteral:
 		return &object.String{Value: node.Value}
 	case *ast.NilLiteral:
@@ -50,6 +103,8 @@ func (e *Evaluator) Evaluate(node ast.Node, env *object.Environment) object.Obje
 		return e.evaluateBlockStatement(node, env)
 	case *ast.IfStatement:
 		return e.evaluateIfStatement(node, env)
+	case *ast.SwitchStatement:
+		return e.evaluateSwitchStatement(node, env)
 	case *ast.ReturnStatement:
 		return e.evaluateReturnStatement(node, env)
 	case *ast.VarStatement:
@@ -61,15 +116,31 @@ func (e *Evaluator) Evaluate(node ast.Node, env *object.Environment) object.Obje
 	case *ast.BatchAssignmentStatement:
 		return e.evaluateBatchAssignmentStatement(node, env)
 	case *ast.FunctionLiteral:
-		return &object.Function{Parameters: node.Parameters, Body: node.Body, Scope: env}
+		return &object.Function{Parameters: node.Parameters, Body: node.Body, Slots: node.Slots, Scope: env}
 	case *ast.FunctionDeclarationStatement:
 		return e.evaluateFunctionDeclaration(node, env)
+	case *ast.StructStatement:
+		return e.evaluateStructStatement(node, env)
+	case *ast.EnumStatement:
+		return e.evaluateEnumStatement(node, env)
 	case *ast.CallExpression:
-		return e.evaluateCallExpression(node, env)
+		return settle(e.evaluateCallExpression(node, env))
+	case *ast.PipeExpression:
+		return e.Evaluate(node.Call, env)
 	case *ast.ArrayLiteral:
 		return e.evaluateArrayLiteral(node, env)
 	case *ast.IndexExpression:
-		return e.evaluateIndexExpression(node, env)
+		return settle(e.evaluateIndexExpression(node, env))
+	case *ast.MemberExpression:
+		return settle(e.evaluateMemberExpression(node, env))
+	case *ast.MatchExpression:
+		return e.evaluateMatchExpression(node, env)
+	case *ast.SliceExpression:
+		return settle(e.evaluateSliceExpression(node, env))
+	case *ast.HashLiteral:
+		return e.evaluateHashLiteral(node, env)
+	case *ast.UpdateExpression:
+		return e.evaluateUpdateExpression(node, env)
 
 	default:
 		return e.throwErr(
@@ -103,12 +174,22 @@ func isError(obj object.Object) bool {
 }
 
 func (e *Evaluator) InitializeNativeFunctions(env *object.Environment) {
-	e.registerNativeFn(env, "len", e.NATIVE_LEN_FUNCTION)
+	e.registerNativeFn(env, "len", e.NATIVE_LEN_FUNCTION, "value")
 	e.registerNativeFn(env, "print", e.NATIVE_PRINT_FUNCTION)
-	e.registerNativeFn(env, "prompt", e.NATIVE_PROMPT_FUNCTION)
+	e.registerNativeFn(env, "prompt", e.NATIVE_PROMPT_FUNCTION, "message")
+	e.registerNativeFn(env, "freeze", e.NATIVE_FREEZE_FUNCTION, "value")
 }
 
-func (e *Evaluator) registerNativeFn(env *object.Environment, name string, fn object.NativeFunctionFn) {
-	fnObject := &object.NativeFunction{Fn: fn}
+// registerNativeFn declares a native, params are the names its arguments
+// can be passed by
+func (e *Evaluator) registerNativeFn(env *object.Environment, name string, fn object.NativeFunctionFn, params ...string) {
+	fnObject := &object.NativeFunction{Fn: fn, Parameters: params}
 	env.Declare(name, fnObject)
 }
+
+// NativeFunctionNames lists every native function, for tooling that needs
+// to know them without running any code
+func NativeFunctionNames() []string {
+	e := New()
+	return e.Prelude().Names()
+}
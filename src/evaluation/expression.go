@@ -2,6 +2,9 @@ package evaluation
 
 import (
 	"math"
+	"math/big"
+	"slices"
+	"strings"
 
 	"github.com/caelondev/monkey/src/ast"
 	"github.com/caelondev/monkey/src/object"
@@ -12,7 +15,7 @@ func (e *Evaluator) evaluateIdentifier(node *ast.Identifier, env *object.Environ
 	e.line = node.GetLine()
 	e.column = node.GetColumn()
 
-	if value, ok := env.Get(node.Value); ok {
+	if value, ok := lookupVariable(node, env); ok {
 		return value
 	}
 
@@ -24,6 +27,50 @@ func (e *Evaluator) evaluateIdentifier(node *ast.Identifier, env *object.Environ
 	)
 }
 
+func lookupVariable(node *ast.Identifier, env *object.Environment) (object.Object, bool) {
+	if node.Binding != nil {
+		return env.GetAt(node.Binding.Depth, node.Binding.Slot)
+	}
+
+	return env.Get(node.Value)
+}
+
+func assignVariable(node *ast.Identifier, env *object.Environment, value object.Object) bool {
+	if node.Binding != nil {
+		return env.AssignAt(node.Binding.Depth, node.Binding.Slot, value)
+	}
+
+	return env.Assign(node.Value, value)
+}
+
+// declareVariable puts a new variable in the current scope, at its resolved
+// slot when the resolver gave it one
+func declareVariable(node *ast.Identifier, env *object.Environment, value object.Object) {
+	if node.Binding != nil {
+		env.DeclareAt(node.Binding.Slot, value)
+		return
+	}
+
+	env.Declare(node.Value, value)
+}
+
+func declareConstant(node *ast.Identifier, env *object.Environment, value object.Object) {
+	if node.Binding != nil {
+		env.DeclareConstantAt(node.Binding.Slot, value)
+		return
+	}
+
+	env.DeclareConstant(node.Value, value)
+}
+
+func isConstant(node *ast.Identifier, env *object.Environment) bool {
+	if node.Binding != nil {
+		return env.IsConstantAt(node.Binding.Depth, node.Binding.Slot)
+	}
+
+	return env.IsConstant(node.Value)
+}
+
 func (e *Evaluator) evaluateExpressions(
 	exprs []ast.Expression,
 	env *object.Environment,
@@ -31,6 +78,16 @@ func (e *Evaluator) evaluateExpressions(
 	var result []object.Object
 
 	for _, expr := range exprs {
+		if spread, ok := expr.(*ast.SpreadExpression); ok {
+			elements := e.evaluateSpread(spread, env)
+			if len(elements) == 1 && isError(elements[0]) {
+				return elements
+			}
+
+			result = append(result, elements...)
+			continue
+		}
+
 		evaluated := e.Evaluate(expr, env)
 
 		if isError(evaluated) {
@@ -43,6 +100,26 @@ func (e *Evaluator) evaluateExpressions(
 	return result
 }
 
+// evaluateSpread gives the elements ...value stands for, or the error alone
+func (e *Evaluator) evaluateSpread(node *ast.SpreadExpression, env *object.Environment) []object.Object {
+	value := e.Evaluate(node.Value, env)
+	if isError(value) {
+		return []object.Object{value}
+	}
+
+	array, ok := value.(*object.Array)
+	if !ok {
+		return []object.Object{e.throwErr(
+			node,
+			"This error occurs when ... is used on something that isn't an array",
+			"Cannot spread a value of type '%s'",
+			value.Type(),
+		)}
+	}
+
+	return array.Elements
+}
+
 func (e *Evaluator) evaluateUnaryExpression(node *ast.UnaryExpression, env *object.Environment) object.Object {
 	right := e.Evaluate(node.Right, env)
 	if isError(right) {
@@ -54,6 +131,8 @@ func (e *Evaluator) evaluateUnaryExpression(node *ast.UnaryExpression, env *obje
 		return e.evaluateNotExpression(right)
 	case token.MINUS:
 		return e.evaluateNegationExpression(node, right)
+	case token.TILDE:
+		return e.evaluateBitwiseNot(node, right)
 	default:
 		return e.throwErr(
 			node,
@@ -72,6 +151,10 @@ func (e *Evaluator) evaluateNegationExpression(node *ast.UnaryExpression, right
 		return &object.Number{Value: -obj.Value}
 	case *object.NaN:
 		return object.NAN
+	case *object.Integer, *object.BigInt:
+		return e.evaluateIntegerNegation(obj)
+	case *object.Decimal:
+		return &object.Decimal{Unscaled: new(big.Int).Neg(obj.Unscaled), Scale: obj.Scale}
 	default:
 		return e.throwErr(
 			node,
@@ -81,15 +164,166 @@ func (e *Evaluator) evaluateNegationExpression(node *ast.UnaryExpression, right
 	}
 }
 
+// The operator each compound assignment applies ---
+var compoundOperators = map[token.TokenType]token.TokenType{
+	token.PLUS_ASSIGN:  token.PLUS,
+	token.MINUS_ASSIGN: token.MINUS,
+	token.STAR_ASSIGN:  token.STAR,
+	token.SLASH_ASSIGN: token.SLASH,
+	token.CARET_ASSIGN: token.CARET,
+	token.INCREMENT:    token.PLUS,
+	token.DECREMENT:    token.MINUS,
+}
+
 func (e *Evaluator) evaluateAssignmentExpression(node *ast.AssignmentExpression, env *object.Environment) object.Object {
+	place := e.evaluatePlace(node.Assignee, env)
+	if err := place.err(); err != nil {
+		return err
+	}
+
 	newValue := e.Evaluate(node.NewValue, env)
-	assignee := node.Assignee.TokenLiteral()
+	if isError(newValue) {
+		return newValue
+	}
+
+	if node.IsCompound() {
+		current := e.readPlace(node, place, env)
+		if isError(current) {
+			return current
+		}
+
+		newValue = e.evaluateCompoundOperation(node.Token, node.Assignee, node.NewValue, current, newValue)
+		if isError(newValue) {
+			return newValue
+		}
+	}
+
+	return e.writePlace(node, place, env, newValue)
+}
+
+func (e *Evaluator) evaluateUpdateExpression(node *ast.UpdateExpression, env *object.Environment) object.Object {
+	place := e.evaluatePlace(node.Target, env)
+	if err := place.err(); err != nil {
+		return err
+	}
+
+	current := e.readPlace(node, place, env)
+	if isError(current) {
+		return current
+	}
+
+	one := &ast.IntegerLiteral{Token: node.Operator, Value: 1}
+	updated := e.evaluateCompoundOperation(node.Operator, node.Target, one, current, &object.Integer{Value: 1})
+	if isError(updated) {
+		return updated
+	}
+
+	if result := e.writePlace(node, place, env, updated); isError(result) {
+		return result
+	}
+
+	if node.Prefix {
+		return updated
+	}
+	return current
+}
+
+// evaluateCompoundOperation applies the operator behind += or ++, errors
+// point at the compound operator
+func (e *Evaluator) evaluateCompoundOperation(op token.Token, left, right ast.Expression, current, operand object.Object) object.Object {
+	operator := op
+	operator.Type = compoundOperators[op.Type]
+	operator.Literal = string(operator.Type)
+
+	binary := &ast.BinaryExpression{Token: op, Operator: operator, Left: left, Right: right}
+	return e.evaluateBinaryOperation(binary, current, operand)
+}
+
+// place is what an assignment writes to: a variable, an element of an
+// already evaluated array or hash, or a field of an instance ---
+type place struct {
+	ident  *ast.Identifier
+	index  object.Object
+	target object.Object
+	node   *ast.IndexExpression
+	member *ast.MemberExpression
+}
+
+// evaluatePlace evaluates an element's target and index once, so a[f()] += 1
+// calls f a single time
+func (e *Evaluator) evaluatePlace(assignee ast.Expression, env *object.Environment) place {
+	switch assignee := assignee.(type) {
+	case *ast.IndexExpression:
+		p := place{node: assignee, target: e.Evaluate(assignee.Target, env)}
+		if !isError(p.target) {
+			p.index = e.Evaluate(assignee.Index, env)
+		}
+		return p
+	case *ast.MemberExpression:
+		return place{member: assignee, target: e.Evaluate(assignee.Target, env)}
+	case *ast.Identifier:
+		return place{ident: assignee}
+	}
+
+	return place{}
+}
+
+// err is the error met while evaluating the element, if any
+func (p place) err() object.Object {
+	for _, obj := range []object.Object{p.target, p.index} {
+		if obj != nil && isError(obj) {
+			return obj
+		}
+	}
+	return nil
+}
+
+func (e *Evaluator) readPlace(node ast.Node, p place, env *object.Environment) object.Object {
+	if p.node != nil {
+		return e.indexValue(p.node, p.target, p.index)
+	}
+
+	if p.member != nil {
+		return e.memberValue(p.member, p.target)
+	}
 
-	if _, ok := env.Get(assignee); ok {
-		value, _ := env.Set(assignee, newValue)
+	if value, ok := lookupVariable(p.ident, env); ok {
 		return value
 	}
 
+	return e.undefinedAssignment(node)
+}
+
+func (e *Evaluator) writePlace(node ast.Node, p place, env *object.Environment, value object.Object) object.Object {
+	if p.node != nil {
+		return e.setIndexValue(p.node, p.target, p.index, value)
+	}
+
+	if p.member != nil {
+		return e.setMemberValue(p.member, p.target, value)
+	}
+
+	if p.ident != nil && isConstant(p.ident, env) {
+		return e.constantAssignment(node, p.ident)
+	}
+
+	if p.ident != nil && assignVariable(p.ident, env, value) {
+		return value
+	}
+
+	return e.undefinedAssignment(node)
+}
+
+func (e *Evaluator) constantAssignment(node ast.Node, ident *ast.Identifier) object.Object {
+	return e.throwErr(
+		node,
+		"This error occurs when a variable declared with const is reassigned, declare it with var to change it",
+		"Cannot assign to '%s' as it is a constant",
+		ident.Value,
+	)
+}
+
+func (e *Evaluator) undefinedAssignment(node ast.Node) object.Object {
 	return e.throwErr(
 		node,
 		"This error occurs when trying to assign a variable that doesn't exist",
@@ -124,12 +358,27 @@ func (e *Evaluator) evaluateBinaryExpression(node *ast.BinaryExpression, env *ob
 	if isError(left) {
 		return left
 	}
+
+	// Unlike other operators, ?? only evaluates its right side when needed ---
+	if node.Operator.Type == token.NULLISH {
+		if left != object.NIL {
+			return left
+		}
+		return e.Evaluate(node.Right, env)
+	}
 	right := e.Evaluate(node.Right, env)
 
 	if isError(right) {
 		return right
 	}
 
+	return e.evaluateBinaryOperation(node, left, right)
+}
+
+// evaluateBinaryOperation applies node's operator to operands that are
+// already evaluated
+func (e *Evaluator) evaluateBinaryOperation(node *ast.BinaryExpression, left, right object.Object) object.Object {
+
 	// Handle NaN operands ---
 	if left.Type() == object.NAN_OBJECT || right.Type() == object.NAN_OBJECT {
 		switch node.Operator.Type {
@@ -142,6 +391,31 @@ func (e *Evaluator) evaluateBinaryExpression(node *ast.BinaryExpression, env *ob
 		}
 	}
 
+	if isBitwiseOperator(node.Operator.Type) &&
+		left.Type() != object.INFINITY_OBJECT && right.Type() != object.INFINITY_OBJECT {
+		return e.evaluateBitwiseExpression(node, left, right)
+	}
+
+	if left.Type() == object.DECIMAL_OBJECT || right.Type() == object.DECIMAL_OBJECT {
+		l, lok := toDecimal(left)
+		r, rok := toDecimal(right)
+		if lok && rok {
+			return e.evaluateDecimalBinaryExpression(node, l, r)
+		}
+	}
+
+	if isInteger(left) && isInteger(right) {
+		return e.evaluateIntegerBinaryExpression(node, left, right)
+	}
+
+	if cmp, ok := compareExactly(left, right); ok {
+		if result, ok := comparisonResult(node.Operator.Type, cmp); ok {
+			return result
+		}
+	}
+
+	left, right = promoteMixed(left, right)
+
 	switch {
 	case left.Type() == object.INFINITY_OBJECT && right.Type() == object.INFINITY_OBJECT:
 		return evalInfInf(node.Operator.Type, left.(*object.Infinity), right.(*object.Infinity))
@@ -156,6 +430,10 @@ func (e *Evaluator) evaluateBinaryExpression(node *ast.BinaryExpression, env *ob
 		return e.evaluateNumericBinaryExpression(node, left, right)
 	case left.Type() == object.STRING_OBJECT && right.Type() == object.STRING_OBJECT:
 		return e.evaluateStringBinaryExpression(node, left, right)
+	case left.Type() == object.VARIANT_OBJECT || right.Type() == object.VARIANT_OBJECT:
+		if result, ok := e.evaluateVariantEquality(node, left, right); ok {
+			return result
+		}
 	}
 
 	return e.throwErr(
@@ -209,6 +487,10 @@ func (e *Evaluator) evaluateNumericBinaryExpression(
 		result = l / r
 	case token.CARET:
 		result = math.Pow(l, r)
+	case token.FLOOR_DIV:
+		result = math.Floor(l / r)
+	case token.PERCENT:
+		result = floorMod(l, r)
 
 	case token.LESS:
 		return e.evaluateToObjectBoolean(l < r)
@@ -251,7 +533,7 @@ func (e *Evaluator) evaluateCallExpression(node *ast.CallExpression, env *object
 	if ident, ok := node.Function.(*ast.Identifier); ok {
 		// If we got here, this means that we're calling a function in a variable
 		fnName := ident.Value
-		foundFn, exists := env.Get(fnName)
+		foundFn, exists := lookupVariable(ident, env)
 
 		if !exists {
 			return e.throwErr(
@@ -263,29 +545,63 @@ func (e *Evaluator) evaluateCallExpression(node *ast.CallExpression, env *object
 		}
 
 		fn = foundFn
-	} else if fnLit, ok := node.Function.(*ast.FunctionLiteral); ok {
-		args := e.evaluateExpressions(node.Arguments, env)
+	} else {
+		// Anything else is called with whatever it evaluates to, ---
+		// like f()() or s.upper() ---
+		fn = e.chainTarget(node.Function, env)
+		if isError(fn) {
+			return fn
+		}
+	}
+
+	// The arguments aren't evaluated either when the call is skipped ---
+	if skipsLink(node.Optional, fn) {
+		return skipped
+	}
+
+	args, named, err := e.evaluateArguments(node.Arguments, env)
+	if err != nil {
+		return err
+	}
+
+	// Hand tail calls back to applyFunction instead of nesting a new one ---
+	if function, ok := fn.(*object.Function); ok && node.Tail {
+		return &object.TailCall{Function: function, Arguments: args, Named: named, CallNode: node}
+	}
 
-		if len(args) == 1 && isError(args[0]) {
-			return args[0]
+	return e.applyFunction(node.Function, node, fn, args, named)
+}
+
+// evaluateArguments splits a call's arguments into the positional ones, with
+// spreads expanded, and the ones passed by name
+func (e *Evaluator) evaluateArguments(
+	nodes []ast.Expression,
+	env *object.Environment,
+) ([]object.Object, []object.NamedArgument, object.Object) {
+	var positional []ast.Expression
+	var named []object.NamedArgument
+
+	for _, node := range nodes {
+		arg, ok := node.(*ast.NamedArgument)
+		if !ok {
+			positional = append(positional, node)
+			continue
 		}
 
-		fnLitObj := e.Evaluate(fnLit, env)
-		fn = fnLitObj
-	} else {
-		return e.throwErr(
-			node.Function,
-			"This error occurs when trying to call an invalid expression as a function",
-			"Unexpected call to an invalid expression",
-		)
+		// The parser keeps named arguments last, so positional ones run first ---
+		value := e.Evaluate(arg.Value, env)
+		if isError(value) {
+			return nil, nil, value
+		}
+		named = append(named, object.NamedArgument{Name: arg.Name, Value: value})
 	}
 
-	args := e.evaluateExpressions(node.Arguments, env)
+	args := e.evaluateExpressions(positional, env)
 	if len(args) == 1 && isError(args[0]) {
-		return args[0]
+		return nil, nil, args[0]
 	}
 
-	return e.applyFunction(node.Function, node, fn, args)
+	return args, named, nil
 }
 
 func (e *Evaluator) applyFunction(
@@ -293,28 +609,48 @@ func (e *Evaluator) applyFunction(
 	callNode *ast.CallExpression,
 	function object.Object,
 	args []object.Object,
+	named []object.NamedArgument,
 ) object.Object {
 
 	switch fn := function.(type) {
 
 	case *object.Function:
-		if len(fn.Parameters) != len(args) {
-			return e.throwErr(
-				callNode,
-				"Argument count mismatch",
-				"Expected %d arguments, got %d",
-				len(fn.Parameters),
-				len(args),
-			)
+		// Trampoline: a tail call comes back as a value and runs here, ---
+		// in place of the call that made it ---
+		for {
+			extendedEnv, err := e.extendFunctionEnv(fn, callNode, args, named)
+			if err != nil {
+				return err
+			}
+
+			if e.tracer != nil {
+				e.tracer.EnterFunction(fn, callNode, extendedEnv)
+			}
+
+			evaluated := e.unwrapFunctionValue(e.Evaluate(fn.Body, extendedEnv))
+
+			if e.tracer != nil {
+				e.tracer.ExitFunction(fn)
+			}
+
+			tail, ok := evaluated.(*object.TailCall)
+			if !ok {
+				return evaluated
+			}
+
+			fn, args, named, callNode = tail.Function, tail.Arguments, tail.Named, tail.CallNode
 		}
 
-		extendedEnv := e.extendFunctionEnv(fn, args)
-		evaluated := e.Evaluate(fn.Body, extendedEnv)
-		return e.unwrapFunctionValue(evaluated)
-
 	case *object.NativeFunction:
+		args, err := e.arrangeNativeArguments(fn, callNode, args, named)
+		if err != nil {
+			return err
+		}
 		return fn.Fn(callNode, args)
 
+	case *object.Struct:
+		return e.construct(callNode, fn, args, named)
+
 	default:
 		return e.throwErr(
 			fnNode,
@@ -325,21 +661,208 @@ func (e *Evaluator) applyFunction(
 	}
 }
 
-func (e *Evaluator) extendFunctionEnv(fn *object.Function, args []object.Object) *object.Environment {
+// extendFunctionEnv binds positional arguments in order, the rest parameter
+// collects what's left, then named arguments fill their parameters and
+// defaults fill whatever is still empty
+func (e *Evaluator) extendFunctionEnv(
+	fn *object.Function,
+	callNode *ast.CallExpression,
+	args []object.Object,
+	named []object.NamedArgument,
+) (*object.Environment, object.Object) {
+	bound := make([]object.Object, len(fn.Parameters))
+	positional := len(fn.Parameters)
+
+	for idx, param := range fn.Parameters {
+		if param.Rest {
+			positional = idx
+			rest := make([]object.Object, 0, max(len(args)-idx, 0))
+			if idx < len(args) {
+				rest = append(rest, args[idx:]...)
+			}
+			bound[idx] = &object.Array{Elements: rest}
+			break
+		}
+
+		if idx < len(args) {
+			bound[idx] = args[idx]
+		}
+	}
+
+	if positional == len(fn.Parameters) && len(args) > positional {
+		return nil, e.throwErr(
+			callNode,
+			"This error occurs when a call passes more arguments than the function has parameters",
+			"Expected at most %s, got %d, %d too many",
+			countOf(positional, "argument"),
+			len(args),
+			len(args)-positional,
+		)
+	}
+
+	for _, arg := range named {
+		idx := slices.IndexFunc(fn.Parameters, func(param *ast.Parameter) bool {
+			return param.Ident() != nil && param.Ident().Value == arg.Name
+		})
+
+		switch {
+		case idx == -1:
+			return nil, e.unknownNamedArgument(callNode, arg.Name)
+		case fn.Parameters[idx].Rest:
+			return nil, e.throwErr(
+				namedArgumentNode(callNode, arg.Name),
+				"This error occurs when a rest parameter is passed by name, it only collects positional arguments",
+				"Cannot pass rest parameter '%s' by name",
+				arg.Name,
+			)
+		case bound[idx] != nil:
+			return nil, e.repeatedArgument(callNode, arg.Name)
+		}
+
+		bound[idx] = arg.Value
+	}
+
+	var missing []string
+	for idx, param := range fn.Parameters {
+		if bound[idx] == nil && param.Default == nil {
+			missing = append(missing, param.Target.String())
+		}
+	}
+	if len(missing) != 0 {
+		min, _ := ast.Arity(fn.Parameters)
+		return nil, e.missingArguments(callNode, missing, min, len(args)+len(named))
+	}
+
 	// fn env is the outer env (for closure) ---
-	env := object.NewEnvironment(fn.Scope)
+	env := object.NewFunctionEnvironment(fn.Scope, fn.Slots)
 
 	for idx, param := range fn.Parameters {
-		// Assign args to params
-		env.Set(param.Value, args[idx])
+		value := bound[idx]
+		if value == nil {
+			// Defaults run on every call and see the parameters before them ---
+			value = e.Evaluate(param.Default, env)
+			if isError(value) {
+				return nil, value
+			}
+		}
+
+		// Plain parameters bind straight to their slot ---
+		if name, ok := param.Target.(*ast.Identifier); ok {
+			declareVariable(name, env, value)
+			continue
+		}
+
+		bindings, err := e.destructure(param.Target, value, nil)
+		if err != nil {
+			return nil, err
+		}
+		for _, bound := range bindings {
+			declareVariable(bound.name, env, bound.value)
+		}
+	}
+
+	return env, nil
+}
+
+// arrangeNativeArguments puts named arguments in the positions the native
+// declared for them. Natives check their own argument counts
+func (e *Evaluator) arrangeNativeArguments(
+	fn *object.NativeFunction,
+	callNode *ast.CallExpression,
+	args []object.Object,
+	named []object.NamedArgument,
+) ([]object.Object, object.Object) {
+	if len(named) == 0 {
+		return args, nil
+	}
+
+	arranged := slices.Clone(args)
+	for _, arg := range named {
+		idx := slices.Index(fn.Parameters, arg.Name)
+
+		switch {
+		case idx == -1:
+			return nil, e.unknownNamedArgument(callNode, arg.Name)
+		case idx < len(arranged) && arranged[idx] != nil:
+			return nil, e.repeatedArgument(callNode, arg.Name)
+		}
+
+		for len(arranged) <= idx {
+			arranged = append(arranged, nil)
+		}
+		arranged[idx] = arg.Value
+	}
+
+	var missing []string
+	for idx, value := range arranged {
+		if value == nil {
+			missing = append(missing, fn.Parameters[idx])
+		}
+	}
+	if len(missing) != 0 {
+		return nil, e.missingArguments(callNode, missing, len(arranged), len(args)+len(named))
+	}
+
+	return arranged, nil
+}
+
+// namedArgumentNode finds where name was passed, for errors to point at
+func namedArgumentNode(callNode *ast.CallExpression, name string) ast.Node {
+	for _, arg := range callNode.Arguments {
+		if named, ok := arg.(*ast.NamedArgument); ok && named.Name == name {
+			return named
+		}
+	}
+
+	return callNode
+}
+
+func (e *Evaluator) unknownNamedArgument(callNode *ast.CallExpression, name string) object.Object {
+	return e.throwErr(
+		namedArgumentNode(callNode, name),
+		"This error occurs when a named argument matches none of the function's parameters",
+		"Function has no parameter named '%s'",
+		name,
+	)
+}
+
+func (e *Evaluator) repeatedArgument(callNode *ast.CallExpression, name string) object.Object {
+	return e.throwErr(
+		namedArgumentNode(callNode, name),
+		"This error occurs when a parameter gets both a positional and a named argument",
+		"Parameter '%s' is given more than once",
+		name,
+	)
+}
+
+// missingArguments names the parameters a call left out
+func (e *Evaluator) missingArguments(callNode *ast.CallExpression, missing []string, min, got int) object.Object {
+	quoted := make([]string, len(missing))
+	for i, name := range missing {
+		quoted[i] = "'" + name + "'"
+	}
+
+	noun := "argument"
+	if len(missing) > 1 {
+		noun += "s"
 	}
 
-	return env
+	return e.throwErr(
+		callNode,
+		"This error occurs when a call leaves out a parameter that has no default",
+		"Missing %s for %s, expected at least %s, got %d",
+		noun,
+		strings.Join(quoted, ", "),
+		countOf(min, "argument"),
+		got,
+	)
 }
 
+// unwrapFunctionValue takes the value out of a return. A call's result is
+// that value, leaving it wrapped made one() + one() add two ReturnValues
 func (e *Evaluator) unwrapFunctionValue(evaluated object.Object) object.Object {
 	if returnValue, ok := evaluated.(*object.ReturnValue); ok {
-		return returnValue
+		return returnValue.Value
 	}
 
 	return evaluated
@@ -347,16 +870,85 @@ func (e *Evaluator) unwrapFunctionValue(evaluated object.Object) object.Object {
 
 func (e *Evaluator) evaluateArrayLiteral(node *ast.ArrayLiteral, env *object.Environment) object.Object {
 	exprs := e.evaluateExpressions(node.Elements, env)
+	if len(exprs) == 1 && isError(exprs[0]) {
+		return exprs[0]
+	}
+
 	return &object.Array{Elements: exprs}
 }
 
+func (e *Evaluator) evaluateHashLiteral(node *ast.HashLiteral, env *object.Environment) object.Object {
+	hash := object.NewHash()
+
+	for i, keyNode := range node.Keys {
+		key := e.Evaluate(keyNode, env)
+		if isError(key) {
+			return key
+		}
+
+		hashable, ok := key.(object.Hashable)
+		if !ok {
+			return e.unhashableKey(keyNode, key)
+		}
+
+		value := e.Evaluate(node.Values[i], env)
+		if isError(value) {
+			return value
+		}
+
+		hash.Set(hashable, value)
+	}
+
+	return hash
+}
+
 func (e *Evaluator) evaluateIndexExpression(node *ast.IndexExpression, env *object.Environment) object.Object {
-	target := e.Evaluate(node.Target, env)
+	target := e.chainTarget(node.Target, env)
+	if isError(target) {
+		return target
+	}
+
+	if skipsLink(node.Optional, target) {
+		return skipped
+	}
+
 	index := e.Evaluate(node.Index, env)
+	if isError(index) {
+		return index
+	}
 
-	switch {
-	case target.Type() == object.ARRAY_OBJECT && index.Type() == object.NUMBER_OBJECT:
-		return e.evaluateArrayIndexExpression(node, target, index)
+	return e.indexValue(node, target, index)
+}
+
+func (e *Evaluator) indexValue(node *ast.IndexExpression, target, index object.Object) object.Object {
+	switch target := target.(type) {
+	case *object.Array:
+		i, err := e.elementPosition(node, target, index, len(target.Elements))
+		if err != nil {
+			return err
+		}
+		return target.Elements[i]
+
+	case *object.String:
+		// Strings index by character, not by byte ---
+		runes := []rune(target.Value)
+		i, err := e.elementPosition(node, target, index, len(runes))
+		if err != nil {
+			return err
+		}
+		return &object.String{Value: string(runes[i])}
+
+	case *object.Hash:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return e.unhashableKey(node.Index, index)
+		}
+
+		// A missing key reads as nil, like an unset variable ---
+		if value, ok := target.Get(key); ok {
+			return value
+		}
+		return object.NIL
 
 	default:
 		return e.throwErr(
@@ -369,19 +961,63 @@ func (e *Evaluator) evaluateIndexExpression(node *ast.IndexExpression, env *obje
 	}
 }
 
-func (e *Evaluator) evaluateArrayIndexExpression(node *ast.IndexExpression, target object.Object, index object.Object) object.Object {
-	t := target.(*object.Array).Elements
-	i := int(index.(*object.Number).Value)
-	maxLen := len(t) - 1
+// setIndexValue stores value as an element of an array or hash, in place
+func (e *Evaluator) setIndexValue(node *ast.IndexExpression, target, index, value object.Object) object.Object {
+	if isFrozen(target) {
+		return e.throwErr(
+			node,
+			"This error occurs when changing a value that went through freeze, copy it into a new array or hash instead",
+			"Cannot change a frozen %s",
+			strings.ToLower(string(target.Type())),
+		)
+	}
+
+	switch {
+	case target.Type() == object.ARRAY_OBJECT:
+		elements := target.(*object.Array).Elements
+
+		i, err := e.elementPosition(node, target, index, len(elements))
+		if err != nil {
+			return err
+		}
+
+		elements[i] = value
+		return value
+
+	case target.Type() == object.HASH_OBJECT:
+		key, ok := index.(object.Hashable)
+		if !ok {
+			return e.unhashableKey(node.Index, index)
+		}
+
+		target.(*object.Hash).Set(key, value)
+		return value
 
-	if i < 0 || i > maxLen {
+	default:
 		return e.throwErr(
-			node.Index,
-			"This error occurs when trying to index an array smaller or bigger than its current length",
-			"Array index '%d' out-of-bounds",
-			i,
+			node,
+			"This error occurs when assigning to an element of something that isn't an array or hash",
+			"Cannot assign to an element of type '%s' with index type of '%s'",
+			target.Type(),
+			index.Type(),
 		)
 	}
+}
 
-	return t[i]
+func (e *Evaluator) unhashableKey(node ast.Node, key object.Object) object.Object {
+	return e.throwErr(
+		node,
+		"This error occurs when a hash key isn't a string, number or boolean",
+		"Cannot use type '%s' as a hash key",
+		key.Type(),
+	)
+}
+
+// floorMod is % for floats, the result has the sign of the divisor
+func floorMod(l, r float64) float64 {
+	m := math.Mod(l, r)
+	if m != 0 && (m < 0) != (r < 0) {
+		m += r
+	}
+	return m
 }
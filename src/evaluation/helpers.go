@@ -14,6 +14,20 @@ func (e *Evaluator) evaluateToObjectBoolean(v bool) *object.Boolean {
 	return object.FALSE
 }
 
+// countOf spells out n of a noun, "1 argument" or "2 arguments"
+func countOf(n int, noun string) string {
+	if n == 1 {
+		return fmt.Sprintf("%d %s", n, noun)
+	}
+	return fmt.Sprintf("%d %ss", n, noun)
+}
+
+// IsTruthy is how conditions see a value, for passes that decide branches
+// ahead of time
+func IsTruthy(obj object.Object) bool {
+	return isTruthy(obj)
+}
+
 func isTruthy(obj object.Object) bool {
 	switch obj := obj.(type) {
 	case *object.Nil:
@@ -25,6 +39,15 @@ func isTruthy(obj object.Object) bool {
 	case *object.Number:
 		return obj.Value != 0
 
+	case *object.Integer:
+		return obj.Value != 0
+
+	case *object.BigInt:
+		return obj.Value.Sign() != 0
+
+	case *object.Decimal:
+		return obj.Unscaled.Sign() != 0
+
 	case *object.NaN:
 		return false
 
@@ -33,6 +56,23 @@ func isTruthy(obj object.Object) bool {
 	}
 }
 
+// valuesEqual compares numbers the way == does, strings by their text and
+// everything else by identity. equal is the node numbers are compared as
+func (e *Evaluator) valuesEqual(equal *ast.BinaryExpression, left, right object.Object) bool {
+	switch {
+	case left == right:
+		return true
+	case left.Type() == object.STRING_OBJECT && right.Type() == object.STRING_OBJECT:
+		return left.(*object.String).Value == right.(*object.String).Value
+	case isNumber(left) && isNumber(right):
+		return e.evaluateBinaryOperation(equal, left, right) == object.TRUE
+	case left.Type() == object.VARIANT_OBJECT && right.Type() == object.VARIANT_OBJECT:
+		return e.variantsEqual(equal, left.(*object.Variant), right.(*object.Variant))
+	}
+
+	return false
+}
+
 func (e *Evaluator) throwErr(node ast.Node, hint string, format string, a ...interface{}) *object.Error {
 	return &object.Error{
 		Line:    node.GetLine(),
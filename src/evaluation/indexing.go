@@ -0,0 +1,189 @@
+package evaluation
+
+import (
+	"math/big"
+	"strings"
+
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/object"
+)
+
+// NOTE: Arrays and strings share their indexing rules. Indices must be ---
+// whole numbers, negative ones count back from the end, and a slice ---
+// [start:end:step] clamps its bounds to the value the way Python does, ---
+// so only a plain index can be out of bounds ---
+
+// elementPosition checks index against a sequence of length elements and
+// gives the position it stands for
+func (e *Evaluator) elementPosition(node *ast.IndexExpression, target, index object.Object, length int) (int, object.Object) {
+	value, err := e.wholeIndex(node.Index, index)
+	if err != nil {
+		return 0, err
+	}
+
+	position := new(big.Int).Set(value)
+	if position.Sign() < 0 {
+		position.Add(position, big.NewInt(int64(length)))
+	}
+
+	if position.Sign() < 0 || position.Cmp(big.NewInt(int64(length))) >= 0 {
+		noun := "Array"
+		if target.Type() == object.STRING_OBJECT {
+			noun = "String"
+		}
+
+		return 0, e.throwErr(
+			node.Index,
+			"This error occurs when an index is past either end, negative indices count back from the last element",
+			"%s index '%s' out-of-bounds for length %d",
+			noun,
+			value.String(),
+			length,
+		)
+	}
+
+	return int(position.Int64()), nil
+}
+
+// wholeIndex refuses indices with a fraction instead of truncating them
+func (e *Evaluator) wholeIndex(node ast.Node, index object.Object) (*big.Int, object.Object) {
+	value, ok := wholeNumber(index)
+	if ok {
+		return value, nil
+	}
+
+	switch index.Type() {
+	case object.NUMBER_OBJECT, object.DECIMAL_OBJECT, object.INFINITY_OBJECT, object.NAN_OBJECT:
+		return nil, e.throwErr(
+			node,
+			"This error occurs when an index has a fraction, use // to get a whole number",
+			"Index '%s' is not a whole number",
+			index.Inspect(),
+		)
+	}
+
+	return nil, e.throwErr(
+		node,
+		"This error occurs when arrays or strings are indexed with something that isn't a number",
+		"Cannot index with type '%s'",
+		index.Type(),
+	)
+}
+
+func (e *Evaluator) evaluateSliceExpression(node *ast.SliceExpression, env *object.Environment) object.Object {
+	target := e.chainTarget(node.Target, env)
+	if isError(target) {
+		return target
+	}
+
+	if skipsLink(node.Optional, target) {
+		return skipped
+	}
+
+	// Checked before the bounds, a hash can't be sliced whatever they are ---
+	switch target.(type) {
+	case *object.Array, *object.String:
+	default:
+		return e.throwErr(
+			node,
+			"This error occurs when slicing something that isn't an array or a string",
+			"Cannot slice a value of type '%s'",
+			target.Type(),
+		)
+	}
+
+	var bounds [3]*big.Int
+	for i, expr := range []ast.Expression{node.Start, node.End, node.Step} {
+		if expr == nil {
+			continue
+		}
+
+		value := e.Evaluate(expr, env)
+		if isError(value) {
+			return value
+		}
+
+		bound, err := e.wholeIndex(expr, value)
+		if err != nil {
+			return err
+		}
+		bounds[i] = bound
+	}
+
+	if bounds[2] != nil && bounds[2].Sign() == 0 {
+		return e.throwErr(
+			node.Step,
+			"This error occurs when a slice steps by 0, it would never reach its end",
+			"Slice step cannot be zero",
+		)
+	}
+
+	if array, ok := target.(*object.Array); ok {
+		positions := slicePositions(len(array.Elements), bounds[0], bounds[1], bounds[2])
+
+		elements := make([]object.Object, len(positions))
+		for i, position := range positions {
+			elements[i] = array.Elements[position]
+		}
+		return &object.Array{Elements: elements}
+	}
+
+	runes := []rune(target.(*object.String).Value)
+	positions := slicePositions(len(runes), bounds[0], bounds[1], bounds[2])
+
+	var out strings.Builder
+	for _, position := range positions {
+		out.WriteRune(runes[position])
+	}
+	return &object.String{Value: out.String()}
+}
+
+// slicePositions lists the positions [start:end:step] picks out of length
+// elements, nil bounds take their defaults for the step's direction
+func slicePositions(length int, start, end, step *big.Int) []int {
+	by := 1
+	if step != nil {
+		by = clampBound(step, -length-1, length+1)
+	}
+
+	// Bounds past either end behave like the end itself ---
+	adjust := func(bound *big.Int, fallback int) int {
+		if bound == nil {
+			return fallback
+		}
+
+		i := clampBound(bound, -length-1, length+1)
+		if i < 0 {
+			i += length
+		}
+
+		if by > 0 {
+			return min(max(i, 0), length)
+		}
+		return min(max(i, -1), length-1)
+	}
+
+	var from, to int
+	if by > 0 {
+		from, to = adjust(start, 0), adjust(end, length)
+	} else {
+		from, to = adjust(start, length-1), adjust(end, -1)
+	}
+
+	var positions []int
+	for i := from; (by > 0 && i < to) || (by < 0 && i > to); i += by {
+		positions = append(positions, i)
+	}
+
+	return positions
+}
+
+func clampBound(value *big.Int, lo, hi int) int {
+	switch {
+	case value.Cmp(big.NewInt(int64(lo))) < 0:
+		return lo
+	case value.Cmp(big.NewInt(int64(hi))) > 0:
+		return hi
+	}
+	return int(value.Int64())
+}
@@ -42,9 +42,12 @@ func evalInfInf(op token.TokenType, l, r *object.Infinity) object.Object {
 	case token.STAR:
 		return infinityWithSign(l.Sign * r.Sign)
 
-	case token.SLASH:
+	case token.SLASH, token.FLOOR_DIV, token.PERCENT:
 		return object.NAN
 
+	case token.AMPERSAND, token.PIPE, token.XOR, token.SHIFT_LEFT, token.SHIFT_RIGHT:
+		return object.NAN // Inf has no bits ---
+
 	case token.CARET:
 		if r.Sign < 0 {
 			return &object.Number{Value: 0}
@@ -82,12 +85,18 @@ func evalInfNum(op token.TokenType, inf *object.Infinity, num *object.Number) ob
 		}
 		return infinityWithSign(inf.Sign * signFromNumber(num.Value))
 
-	case token.SLASH:
+	case token.SLASH, token.FLOOR_DIV:
 		if num.Value == 0 {
 			return infinityWithSign(inf.Sign)
 		}
 		return infinityWithSign(inf.Sign * signFromNumber(num.Value))
 
+	case token.PERCENT:
+		return object.NAN
+
+	case token.AMPERSAND, token.PIPE, token.XOR, token.SHIFT_LEFT, token.SHIFT_RIGHT:
+		return object.NAN
+
 	case token.CARET:
 		if num.Value == 0 {
 			return &object.Number{Value: 1}
@@ -137,6 +146,23 @@ func evalNumInf(op token.TokenType, num *object.Number, inf *object.Infinity) ob
 	case token.SLASH:
 		return &object.Number{Value: math.Copysign(0, num.Value)}
 
+	case token.FLOOR_DIV:
+		// Floors towards -Inf when the signs differ ---
+		if num.Value != 0 && signFromNumber(num.Value) != inf.Sign {
+			return &object.Number{Value: -1}
+		}
+		return &object.Number{Value: math.Copysign(0, num.Value*float64(inf.Sign))}
+
+	case token.AMPERSAND, token.PIPE, token.XOR, token.SHIFT_LEFT, token.SHIFT_RIGHT:
+		return object.NAN
+
+	case token.PERCENT:
+		// The remainder takes the divisor's sign ---
+		if num.Value != 0 && signFromNumber(num.Value) != inf.Sign {
+			return infinityWithSign(inf.Sign)
+		}
+		return &object.Number{Value: num.Value}
+
 	case token.CARET:
 		absNum := math.Abs(num.Value)
 
@@ -0,0 +1,325 @@
+package evaluation
+
+import (
+	"math"
+	"math/big"
+
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/token"
+)
+
+// NOTE: Integers stay exact among themselves. Overflowing an int64 ---
+// moves the result to a BigInt, and meeting a float (or Inf/NaN) ---
+// turns the integer into a float so the usual number rules apply. ---
+// / on a BigInt never goes through a float, its fractions are decimals ---
+// Both // and % floor, the remainder takes the sign of the divisor ---
+
+func isInteger(obj object.Object) bool {
+	switch obj.(type) {
+	case *object.Integer, *object.BigInt:
+		return true
+	}
+	return false
+}
+
+// promoteMixed turns the exact side of an exact/float pair into a float
+func promoteMixed(left, right object.Object) (object.Object, object.Object) {
+	isFloat := func(obj object.Object) bool {
+		return obj.Type() == object.NUMBER_OBJECT || obj.Type() == object.INFINITY_OBJECT
+	}
+	isExact := func(obj object.Object) bool {
+		return isInteger(obj) || obj.Type() == object.DECIMAL_OBJECT
+	}
+
+	if isExact(left) && isFloat(right) {
+		return &object.Number{Value: toFloat(left)}, right
+	}
+	if isFloat(left) && isExact(right) {
+		return left, &object.Number{Value: toFloat(right)}
+	}
+
+	return left, right
+}
+
+// compareExactly orders an integer against a float without rounding the
+// integer first, 2^53 + 1 isn't equal to 2^53 as a float
+func compareExactly(left, right object.Object) (int, bool) {
+	if !(isInteger(left) && right.Type() == object.NUMBER_OBJECT) &&
+		!(left.Type() == object.NUMBER_OBJECT && isInteger(right)) {
+		return 0, false
+	}
+
+	return toRat(left).Cmp(toRat(right)), true
+}
+
+func comparisonResult(op token.TokenType, cmp int) (object.Object, bool) {
+	switch op {
+	case token.LESS:
+		return eBool(cmp < 0), true
+	case token.GREATER:
+		return eBool(cmp > 0), true
+	case token.LESS_EQUAL:
+		return eBool(cmp <= 0), true
+	case token.GREATER_EQUAL:
+		return eBool(cmp >= 0), true
+	case token.EQUAL:
+		return eBool(cmp == 0), true
+	case token.NOT_EQUAL:
+		return eBool(cmp != 0), true
+	}
+
+	return nil, false
+}
+
+func toRat(obj object.Object) *big.Rat {
+	switch obj := obj.(type) {
+	case *object.Number:
+		return new(big.Rat).SetFloat64(obj.Value)
+	case *object.Decimal:
+		return decimalRat(obj)
+	}
+	return new(big.Rat).SetInt(toBig(obj))
+}
+
+func toFloat(obj object.Object) float64 {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return float64(obj.Value)
+	case *object.BigInt:
+		value, _ := new(big.Float).SetInt(obj.Value).Float64()
+		return value
+	case *object.Decimal:
+		value, _ := decimalRat(obj).Float64()
+		return value
+	case *object.Number:
+		return obj.Value
+	}
+	return math.NaN()
+}
+
+func toBig(obj object.Object) *big.Int {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		return big.NewInt(obj.Value)
+	case *object.BigInt:
+		return obj.Value
+	}
+	return new(big.Int)
+}
+
+func (e *Evaluator) evaluateIntegerBinaryExpression(node *ast.BinaryExpression, left, right object.Object) object.Object {
+	l, lok := left.(*object.Integer)
+	r, rok := right.(*object.Integer)
+
+	if !lok || !rok {
+		return e.evaluateBigIntBinaryExpression(node, toBig(left), toBig(right))
+	}
+
+	a, b := l.Value, r.Value
+
+	switch node.Operator.Type {
+	case token.PLUS:
+		sum := a + b
+		if (a >= 0) == (b >= 0) && (sum >= 0) != (a >= 0) {
+			return e.evaluateBigIntBinaryExpression(node, big.NewInt(a), big.NewInt(b))
+		}
+		return &object.Integer{Value: sum}
+
+	case token.MINUS:
+		diff := a - b
+		if (a >= 0) != (b >= 0) && (diff >= 0) != (a >= 0) {
+			return e.evaluateBigIntBinaryExpression(node, big.NewInt(a), big.NewInt(b))
+		}
+		return &object.Integer{Value: diff}
+
+	case token.STAR:
+		if a == 0 || b == 0 {
+			return &object.Integer{Value: 0}
+		}
+		product := a * b
+		if product/b != a || (a == -1 && b == math.MinInt64) || (b == -1 && a == math.MinInt64) {
+			return e.evaluateBigIntBinaryExpression(node, big.NewInt(a), big.NewInt(b))
+		}
+		return &object.Integer{Value: product}
+
+	case token.SLASH:
+		// An even division stays an integer, a fraction (or a zero ---
+		// divisor, for Infinity) goes through the float rules ---
+		if b == 0 || a%b != 0 {
+			return e.evaluateNumericBinaryExpression(node, &object.Number{Value: float64(a)}, &object.Number{Value: float64(b)})
+		}
+		if a == math.MinInt64 && b == -1 {
+			return e.evaluateBigIntBinaryExpression(node, big.NewInt(a), big.NewInt(b))
+		}
+		return &object.Integer{Value: a / b}
+
+	case token.FLOOR_DIV, token.PERCENT:
+		if b == 0 {
+			return e.divisionByZero(node)
+		}
+		if a == math.MinInt64 && b == -1 {
+			return e.evaluateBigIntBinaryExpression(node, big.NewInt(a), big.NewInt(b))
+		}
+
+		quotient, remainder := a/b, a%b
+		if remainder != 0 && (remainder < 0) != (b < 0) {
+			quotient--
+			remainder += b
+		}
+
+		if node.Operator.Type == token.PERCENT {
+			return &object.Integer{Value: remainder}
+		}
+		return &object.Integer{Value: quotient}
+
+	case token.CARET:
+		if b < 0 {
+			return e.evaluateNumericBinaryExpression(node, &object.Number{Value: float64(a)}, &object.Number{Value: float64(b)})
+		}
+
+		if powerTooLarge(big.NewInt(a), big.NewInt(b)) {
+			return e.powerOverflow(node, big.NewInt(b))
+		}
+
+		result := new(big.Int).Exp(big.NewInt(a), big.NewInt(b), nil)
+		if result.IsInt64() {
+			return &object.Integer{Value: result.Int64()}
+		}
+		return &object.BigInt{Value: result}
+
+	case token.LESS:
+		return e.evaluateToObjectBoolean(a < b)
+	case token.GREATER:
+		return e.evaluateToObjectBoolean(a > b)
+	case token.LESS_EQUAL:
+		return e.evaluateToObjectBoolean(a <= b)
+	case token.GREATER_EQUAL:
+		return e.evaluateToObjectBoolean(a >= b)
+	case token.EQUAL:
+		return e.evaluateToObjectBoolean(a == b)
+	case token.NOT_EQUAL:
+		return e.evaluateToObjectBoolean(a != b)
+	}
+
+	return e.unknownIntegerOperator(node)
+}
+
+func (e *Evaluator) evaluateBigIntBinaryExpression(node *ast.BinaryExpression, l, r *big.Int) object.Object {
+	switch node.Operator.Type {
+	case token.PLUS:
+		return &object.BigInt{Value: new(big.Int).Add(l, r)}
+	case token.MINUS:
+		return &object.BigInt{Value: new(big.Int).Sub(l, r)}
+	case token.STAR:
+		return &object.BigInt{Value: new(big.Int).Mul(l, r)}
+
+	case token.SLASH:
+		// Big integers never round through a float: an even division ---
+		// stays a BigInt and a fraction becomes a decimal. Only a zero ---
+		// divisor goes through the float rules, for Infinity ---
+		if r.Sign() == 0 {
+			return e.evaluateNumericBinaryExpression(node, &object.Number{Value: toFloat(&object.BigInt{Value: l})}, &object.Number{Value: 0})
+		}
+
+		quotient, remainder := new(big.Int).QuoRem(l, r, new(big.Int))
+		if remainder.Sign() == 0 {
+			return &object.BigInt{Value: quotient}
+		}
+		return divideDecimals(&object.Decimal{Unscaled: l}, &object.Decimal{Unscaled: r})
+
+	case token.FLOOR_DIV, token.PERCENT:
+		if r.Sign() == 0 {
+			return e.divisionByZero(node)
+		}
+
+		quotient, remainder := new(big.Int).QuoRem(l, r, new(big.Int))
+		if remainder.Sign() != 0 && remainder.Sign() != r.Sign() {
+			quotient.Sub(quotient, big.NewInt(1))
+			remainder.Add(remainder, r)
+		}
+
+		if node.Operator.Type == token.PERCENT {
+			return &object.BigInt{Value: remainder}
+		}
+		return &object.BigInt{Value: quotient}
+
+	case token.CARET:
+		if r.Sign() < 0 {
+			return e.evaluateNumericBinaryExpression(node,
+				&object.Number{Value: toFloat(&object.BigInt{Value: l})},
+				&object.Number{Value: toFloat(&object.BigInt{Value: r})})
+		}
+		if powerTooLarge(l, r) {
+			return e.powerOverflow(node, r)
+		}
+		return &object.BigInt{Value: new(big.Int).Exp(l, r, nil)}
+
+	case token.LESS:
+		return e.evaluateToObjectBoolean(l.Cmp(r) < 0)
+	case token.GREATER:
+		return e.evaluateToObjectBoolean(l.Cmp(r) > 0)
+	case token.LESS_EQUAL:
+		return e.evaluateToObjectBoolean(l.Cmp(r) <= 0)
+	case token.GREATER_EQUAL:
+		return e.evaluateToObjectBoolean(l.Cmp(r) >= 0)
+	case token.EQUAL:
+		return e.evaluateToObjectBoolean(l.Cmp(r) == 0)
+	case token.NOT_EQUAL:
+		return e.evaluateToObjectBoolean(l.Cmp(r) != 0)
+	}
+
+	return e.unknownIntegerOperator(node)
+}
+
+func (e *Evaluator) evaluateIntegerNegation(obj object.Object) object.Object {
+	switch obj := obj.(type) {
+	case *object.Integer:
+		if obj.Value == math.MinInt64 {
+			return &object.BigInt{Value: new(big.Int).Neg(big.NewInt(obj.Value))}
+		}
+		return &object.Integer{Value: -obj.Value}
+	case *object.BigInt:
+		return &object.BigInt{Value: new(big.Int).Neg(obj.Value)}
+	}
+	return object.NAN
+}
+
+// powerTooLarge tells whether base ^ exponent would need more bits than the
+// longest shift, 2 ^ n is as large as 1 << n
+func powerTooLarge(base, exponent *big.Int) bool {
+	bits := new(big.Int).Abs(base).BitLen() - 1
+	if bits <= 0 {
+		return false // 0, 1 and -1 stay small whatever the exponent ---
+	}
+
+	size := new(big.Int).Mul(big.NewInt(int64(bits)), exponent)
+	return size.Cmp(big.NewInt(maxShift)) > 0
+}
+
+func (e *Evaluator) powerOverflow(node *ast.BinaryExpression, exponent *big.Int) object.Object {
+	return e.throwErr(
+		node,
+		"This error occurs when an exact power would be absurdly large, use a float base to get Infinity instead",
+		"Cannot raise to %v, the result would be over %d bits",
+		exponent,
+		maxShift,
+	)
+}
+
+func (e *Evaluator) divisionByZero(node *ast.BinaryExpression) object.Object {
+	return e.throwErr(
+		node,
+		"This error occurs when an integer is divided by zero with // or %. Use / to get Infinity instead",
+		"Integer division by zero",
+	)
+}
+
+func (e *Evaluator) unknownIntegerOperator(node *ast.BinaryExpression) object.Object {
+	return e.throwErr(
+		node,
+		"This error occurs when an unregistered binary operator is used.\nThis should only appear during language development.",
+		"Unknown integer operator: '%v'",
+		node.Operator.Type,
+	)
+}
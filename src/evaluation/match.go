@@ -0,0 +1,266 @@
+package evaluation
+
+import (
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/token"
+)
+
+// NOTE: Arms are tried in order. A pattern either fits the value, ---
+// giving the names it binds, or doesn't and the next arm is tried. ---
+// Only mistakes in the pattern itself, like asking a struct for a ---
+// field it doesn't have, are errors. The names live in a scope of ---
+// their own that the guard and the result share ---
+
+func (e *Evaluator) evaluateMatchExpression(node *ast.MatchExpression, env *object.Environment) object.Object {
+	subject := e.Evaluate(node.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	for _, arm := range node.Arms {
+		bindings, ok, err := e.matchPattern(arm.Pattern, subject, env, nil)
+		if err != nil {
+			return err
+		}
+		if !ok {
+			continue
+		}
+
+		armEnv := object.NewEnvironment(env)
+		for _, bound := range bindings {
+			declareVariable(bound.name, armEnv, bound.value)
+		}
+
+		if arm.Guard != nil {
+			guard := e.Evaluate(arm.Guard, armEnv)
+			if isError(guard) {
+				return guard
+			}
+			if !isTruthy(guard) {
+				continue
+			}
+		}
+
+		return e.Evaluate(arm.Body, armEnv)
+	}
+
+	return e.throwErr(
+		node,
+		"This error occurs when no arm of a match fits the value, end it with a _ arm to catch the rest",
+		"No match arm fits the value %s",
+		subject.Inspect(),
+	)
+}
+
+// matchPattern tells whether value fits pattern, and if so adds what it
+// binds to bindings
+func (e *Evaluator) matchPattern(
+	pattern ast.Pattern,
+	value object.Object,
+	env *object.Environment,
+	bindings []binding,
+) ([]binding, bool, object.Object) {
+	switch pattern := pattern.(type) {
+	case *ast.WildcardPattern:
+		return bindings, true, nil
+
+	case *ast.Identifier:
+		return append(bindings, binding{pattern, value}), true, nil
+
+	case *ast.LiteralPattern:
+		expected := e.Evaluate(pattern.Value, env)
+		if isError(expected) {
+			return nil, false, expected
+		}
+		return bindings, e.valuesEqual(comparison(pattern.Value, token.EQUAL), expected, value), nil
+
+	case *ast.ArrayPattern:
+		array, ok := value.(*object.Array)
+		count := len(pattern.Elements)
+		if !ok || len(array.Elements) < count || (pattern.Rest == nil && len(array.Elements) > count) {
+			return nil, false, nil
+		}
+
+		for i, elem := range pattern.Elements {
+			var err object.Object
+			bindings, ok, err = e.matchPattern(elem, array.Elements[i], env, bindings)
+			if err != nil || !ok {
+				return nil, false, err
+			}
+		}
+
+		if pattern.Rest != nil {
+			rest := make([]object.Object, len(array.Elements)-count)
+			copy(rest, array.Elements[count:])
+			bindings = append(bindings, binding{pattern.Rest, &object.Array{Elements: rest}})
+		}
+		return bindings, true, nil
+
+	case *ast.HashPattern:
+		// Unlike var, a key that's missing makes the pattern not fit ---
+		hash, ok := value.(*object.Hash)
+		if !ok {
+			return nil, false, nil
+		}
+
+		taken := make(map[object.HashKey]bool, len(pattern.Keys))
+		for i, key := range pattern.Keys {
+			name := &object.String{Value: key.Value}
+			taken[name.HashKey()] = true
+
+			element, found := hash.Get(name)
+			if !found {
+				return nil, false, nil
+			}
+
+			var err object.Object
+			bindings, ok, err = e.matchPattern(pattern.Values[i], element, env, bindings)
+			if err != nil || !ok {
+				return nil, false, err
+			}
+		}
+
+		if pattern.Rest != nil {
+			rest := object.NewHash()
+			hash.Each(func(pair *object.HashPair) {
+				if key := pair.Key.(object.Hashable); !taken[key.HashKey()] {
+					rest.Set(key, pair.Value)
+				}
+			})
+			bindings = append(bindings, binding{pattern.Rest, rest})
+		}
+		return bindings, true, nil
+
+	case *ast.ConstructorPattern:
+		return e.matchConstructor(pattern, value, env, bindings)
+	}
+
+	return bindings, false, nil
+}
+
+// matchConstructor fits an instance of a struct or a variant of an enum,
+// then the patterns given for its fields
+func (e *Evaluator) matchConstructor(
+	pattern *ast.ConstructorPattern,
+	value object.Object,
+	env *object.Environment,
+	bindings []binding,
+) ([]binding, bool, object.Object) {
+	var name string
+	var fields []string
+	var values []object.Object
+	fits := false
+
+	if member, ok := pattern.Type.(*ast.MemberExpression); ok {
+		target := e.Evaluate(member.Target, env)
+		if isError(target) {
+			return nil, false, target
+		}
+
+		enum, ok := target.(*object.Enum)
+		if !ok {
+			return nil, false, e.throwErr(
+				member.Target,
+				"This error occurs when a pattern names a variant of something that isn't an enum",
+				"Cannot match a variant of type '%s', expected an enum",
+				target.Type(),
+			)
+		}
+
+		decl := enum.Variant(member.Property)
+		if decl == nil {
+			return nil, false, e.enumMember(member, enum)
+		}
+
+		name = enum.Name + "." + decl.Name.Value
+		for _, field := range decl.Fields {
+			fields = append(fields, field.Value)
+		}
+
+		variant, ok := value.(*object.Variant)
+		if fits = ok && variant.Decl == decl; fits {
+			values = variant.Values
+		}
+	} else {
+		target := e.Evaluate(pattern.Type, env)
+		if isError(target) {
+			return nil, false, target
+		}
+
+		structure, ok := target.(*object.Struct)
+		if !ok {
+			return nil, false, e.throwErr(
+				pattern.Type,
+				"This error occurs when a pattern written like Point(x, y) names something that isn't a struct",
+				"Cannot match against type '%s', expected a struct",
+				target.Type(),
+			)
+		}
+
+		name = structure.Name
+		for _, field := range structure.Fields {
+			fields = append(fields, field.Ident().Value)
+		}
+
+		instance, ok := value.(*object.Instance)
+		if fits = ok && instance.Struct == structure; fits {
+			values = instance.Values
+		}
+	}
+
+	// Checked even when the value doesn't fit, a broken pattern is a bug ---
+	if len(pattern.Elements) > len(fields) {
+		return nil, false, e.throwErr(
+			pattern,
+			"This error occurs when a pattern lists more values than there are fields, name them instead to be sure",
+			"Pattern gives %d values but '%s' has %d fields",
+			len(pattern.Elements),
+			name,
+			len(fields),
+		)
+	}
+
+	positions := make([]int, len(pattern.Keys))
+	for i, key := range pattern.Keys {
+		positions[i] = -1
+		for j, field := range fields {
+			if field == key.Value {
+				positions[i] = j
+			}
+		}
+
+		if positions[i] == -1 {
+			return nil, false, e.throwErr(
+				key,
+				"This error occurs when a pattern names a field the struct or variant doesn't declare",
+				"'%s' has no field '%s'",
+				name,
+				key.Value,
+			)
+		}
+	}
+
+	if !fits {
+		return nil, false, nil
+	}
+
+	var ok bool
+	var err object.Object
+
+	for i, elem := range pattern.Elements {
+		bindings, ok, err = e.matchPattern(elem, values[i], env, bindings)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+	}
+
+	for i, sub := range pattern.Values {
+		bindings, ok, err = e.matchPattern(sub, values[positions[i]], env, bindings)
+		if err != nil || !ok {
+			return nil, false, err
+		}
+	}
+
+	return bindings, true, nil
+}
@@ -0,0 +1,523 @@
+package evaluation
+
+import (
+	"sort"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/token"
+)
+
+// NOTE: value.name looks name up in the table of the value's type. ---
+// Properties are read on the spot, methods come back as a native ---
+// function bound to the value, so s.upper() and var f = s.upper; f() ---
+// behave the same. Methods declare parameter names like natives do, ---
+// and check their own argument counts ---
+
+type method struct {
+	params   []string // Names the arguments can be passed by ---
+	required int      // How many of params must be given ---
+	variadic bool     // The last parameter takes any number of arguments ---
+	fn       func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object
+}
+
+type property func(receiver object.Object) object.Object
+
+var properties = map[object.ObjectType]map[string]property{
+	object.STRING_OBJECT: {
+		"len": func(receiver object.Object) object.Object {
+			return &object.Integer{Value: int64(utf8.RuneCountInString(receiver.(*object.String).Value))}
+		},
+	},
+	object.ARRAY_OBJECT: {
+		"len": func(receiver object.Object) object.Object {
+			return &object.Integer{Value: int64(len(receiver.(*object.Array).Elements))}
+		},
+	},
+	object.HASH_OBJECT: {
+		"len": func(receiver object.Object) object.Object {
+			return &object.Integer{Value: int64(len(receiver.(*object.Hash).Order))}
+		},
+	},
+}
+
+var methods map[object.ObjectType]map[string]*method
+
+func init() {
+	// Set here, the array methods call back into the evaluator ---
+	methods = map[object.ObjectType]map[string]*method{
+		object.STRING_OBJECT: stringMethods,
+		object.ARRAY_OBJECT:  arrayMethods,
+		object.HASH_OBJECT:   hashMethods,
+	}
+}
+
+// MemberNames lists the properties and methods of a type, for tooling that
+// completes them
+func MemberNames(objectType object.ObjectType) []string {
+	var names []string
+	for name := range properties[objectType] {
+		names = append(names, name)
+	}
+	for name := range methods[objectType] {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func (e *Evaluator) evaluateMemberExpression(node *ast.MemberExpression, env *object.Environment) object.Object {
+	target := e.chainTarget(node.Target, env)
+	if isError(target) {
+		return target
+	}
+
+	if skipsLink(node.Optional, target) {
+		return skipped
+	}
+
+	return e.memberValue(node, target)
+}
+
+func (e *Evaluator) memberValue(node *ast.MemberExpression, target object.Object) object.Object {
+	switch target := target.(type) {
+	case *object.Instance:
+		return e.instanceMember(node, target)
+	case *object.Enum:
+		return e.enumMember(node, target)
+	case *object.Variant:
+		return e.variantMember(node, target)
+	}
+
+	if read, ok := properties[target.Type()][node.Property]; ok {
+		return read(target)
+	}
+
+	m, ok := methods[target.Type()][node.Property]
+	if !ok {
+		return e.throwErr(
+			node,
+			"This error occurs when a value has no property or method of that name",
+			"Type '%s' has no member '%s'",
+			target.Type(),
+			node.Property,
+		)
+	}
+
+	return &object.NativeFunction{
+		Parameters: m.params,
+		Fn: func(call *ast.CallExpression, args []object.Object) object.Object {
+			if err := e.checkMethodArity(call, node.Property, m, args); err != nil {
+				return err
+			}
+			return m.fn(e, call, target, args)
+		},
+	}
+}
+
+func (e *Evaluator) checkMethodArity(call *ast.CallExpression, name string, m *method, args []object.Object) object.Object {
+	if len(args) >= m.required && (m.variadic || len(args) <= len(m.params)) {
+		return nil
+	}
+
+	expected := "exactly"
+	switch {
+	case m.variadic:
+		expected = "at least"
+	case m.required != len(m.params) && len(args) < m.required:
+		expected = "at least"
+	case m.required != len(m.params):
+		expected = "at most"
+	}
+
+	count := m.required
+	if expected == "at most" {
+		count = len(m.params)
+	}
+
+	return e.throwErr(
+		call,
+		"This error occurs when a method gets more or fewer arguments than it takes",
+		"Method '%s' expects %s %s, got %d",
+		name,
+		expected,
+		countOf(count, "argument"),
+		len(args),
+	)
+}
+
+/* * [ STRING ] **/
+
+var stringMethods = map[string]*method{
+	"upper": {fn: stringMapper(strings.ToUpper)},
+	"lower": {fn: stringMapper(strings.ToLower)},
+	"trim":  {fn: stringMapper(strings.TrimSpace)},
+
+	"split": {params: []string{"separator"}, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		sep := ""
+		if len(args) == 1 {
+			s, err := e.stringArgument(call, args, 0)
+			if err != nil {
+				return err
+			}
+			sep = s
+		}
+
+		// Without a separator, or with "", a string splits into characters ---
+		var parts []string
+		if sep == "" {
+			parts = strings.Split(receiver.(*object.String).Value, "")
+		} else {
+			parts = strings.Split(receiver.(*object.String).Value, sep)
+		}
+
+		elements := make([]object.Object, len(parts))
+		for i, part := range parts {
+			elements[i] = &object.String{Value: part}
+		}
+		return &object.Array{Elements: elements}
+	}},
+
+	"replace": {params: []string{"old", "new"}, required: 2, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		old, err := e.stringArgument(call, args, 0)
+		if err != nil {
+			return err
+		}
+		replacement, err := e.stringArgument(call, args, 1)
+		if err != nil {
+			return err
+		}
+
+		return &object.String{Value: strings.ReplaceAll(receiver.(*object.String).Value, old, replacement)}
+	}},
+
+	"contains":   {params: []string{"part"}, required: 1, fn: stringPredicate(strings.Contains)},
+	"startsWith": {params: []string{"prefix"}, required: 1, fn: stringPredicate(strings.HasPrefix)},
+	"endsWith":   {params: []string{"suffix"}, required: 1, fn: stringPredicate(strings.HasSuffix)},
+
+	"indexOf": {params: []string{"part"}, required: 1, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		part, err := e.stringArgument(call, args, 0)
+		if err != nil {
+			return err
+		}
+
+		// Counted in characters, to match s[i] ---
+		value := receiver.(*object.String).Value
+		i := strings.Index(value, part)
+		if i != -1 {
+			i = utf8.RuneCountInString(value[:i])
+		}
+		return &object.Integer{Value: int64(i)}
+	}},
+}
+
+func stringMapper(convert func(string) string) func(*Evaluator, *ast.CallExpression, object.Object, []object.Object) object.Object {
+	return func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		return &object.String{Value: convert(receiver.(*object.String).Value)}
+	}
+}
+
+func stringPredicate(test func(s, part string) bool) func(*Evaluator, *ast.CallExpression, object.Object, []object.Object) object.Object {
+	return func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		part, err := e.stringArgument(call, args, 0)
+		if err != nil {
+			return err
+		}
+		return eBool(test(receiver.(*object.String).Value, part))
+	}
+}
+
+func (e *Evaluator) stringArgument(call *ast.CallExpression, args []object.Object, i int) (string, object.Object) {
+	s, ok := args[i].(*object.String)
+	if !ok {
+		return "", e.throwErr(
+			argumentNode(call, i),
+			"This error occurs when a method that works on text gets something else",
+			"Expected a string, got type '%s'",
+			args[i].Type(),
+		)
+	}
+	return s.Value, nil
+}
+
+/* * [ ARRAY ] **/
+
+var arrayMethods = map[string]*method{
+	"push": {params: []string{"values"}, variadic: true, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		array := receiver.(*object.Array)
+		if err := e.checkNotFrozen(call, array); err != nil {
+			return err
+		}
+
+		array.Elements = append(array.Elements, args...)
+		return array
+	}},
+
+	"pop": {fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		array := receiver.(*object.Array)
+		if err := e.checkNotFrozen(call, array); err != nil {
+			return err
+		}
+
+		if len(array.Elements) == 0 {
+			return e.throwErr(
+				call,
+				"This error occurs when pop is called on an array with no elements left",
+				"Cannot pop from an empty array",
+			)
+		}
+
+		last := array.Elements[len(array.Elements)-1]
+		array.Elements = array.Elements[:len(array.Elements)-1]
+		return last
+	}},
+
+	"map": {params: []string{"callback"}, required: 1, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		elements := receiver.(*object.Array).Elements
+		mapped := make([]object.Object, 0, len(elements))
+
+		for _, elem := range elements {
+			value := e.callArgument(call, args[0], elem)
+			if isError(value) {
+				return value
+			}
+			mapped = append(mapped, value)
+		}
+		return &object.Array{Elements: mapped}
+	}},
+
+	"filter": {params: []string{"callback"}, required: 1, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		var kept []object.Object
+
+		for _, elem := range receiver.(*object.Array).Elements {
+			keep := e.callArgument(call, args[0], elem)
+			if isError(keep) {
+				return keep
+			}
+			if isTruthy(keep) {
+				kept = append(kept, elem)
+			}
+		}
+		return &object.Array{Elements: kept}
+	}},
+
+	"find": {params: []string{"callback"}, required: 1, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		for _, elem := range receiver.(*object.Array).Elements {
+			found := e.callArgument(call, args[0], elem)
+			if isError(found) {
+				return found
+			}
+			if isTruthy(found) {
+				return elem
+			}
+		}
+		return object.NIL
+	}},
+
+	"reduce": {params: []string{"callback", "initial"}, required: 1, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		elements := receiver.(*object.Array).Elements
+
+		// Without an initial value the first element starts things off ---
+		if len(args) == 1 {
+			if len(elements) == 0 {
+				return e.throwErr(
+					call,
+					"This error occurs when an empty array is reduced without an initial value",
+					"Cannot reduce an empty array without an initial value",
+				)
+			}
+			args = append(args, elements[0])
+			elements = elements[1:]
+		}
+
+		accumulator := args[1]
+		for _, elem := range elements {
+			accumulator = e.callArgument(call, args[0], accumulator, elem)
+			if isError(accumulator) {
+				return accumulator
+			}
+		}
+		return accumulator
+	}},
+
+	"reverse": {fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		elements := receiver.(*object.Array).Elements
+
+		reversed := make([]object.Object, len(elements))
+		for i, elem := range elements {
+			reversed[len(elements)-1-i] = elem
+		}
+		return &object.Array{Elements: reversed}
+	}},
+
+	"sort": {params: []string{"less"}, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		sorted := append([]object.Object(nil), receiver.(*object.Array).Elements...)
+
+		// less(a, b) says whether a goes before b, the default is a < b ---
+		var failure object.Object
+		sort.SliceStable(sorted, func(i, j int) bool {
+			if failure != nil {
+				return false
+			}
+
+			var result object.Object
+			if len(args) == 1 {
+				result = e.callArgument(call, args[0], sorted[i], sorted[j])
+			} else {
+				result = e.lessThan(call, sorted[i], sorted[j])
+			}
+
+			if isError(result) {
+				failure = result
+				return false
+			}
+			return isTruthy(result)
+		})
+
+		if failure != nil {
+			return failure
+		}
+		return &object.Array{Elements: sorted}
+	}},
+
+	"join": {params: []string{"separator"}, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		sep := ""
+		if len(args) == 1 {
+			s, err := e.stringArgument(call, args, 0)
+			if err != nil {
+				return err
+			}
+			sep = s
+		}
+
+		// Strings join as their text, like print shows them ---
+		parts := make([]string, 0, len(receiver.(*object.Array).Elements))
+		for _, elem := range receiver.(*object.Array).Elements {
+			if s, ok := elem.(*object.String); ok {
+				parts = append(parts, s.Value)
+			} else {
+				parts = append(parts, elem.Inspect())
+			}
+		}
+		return &object.String{Value: strings.Join(parts, sep)}
+	}},
+
+	"contains": {params: []string{"value"}, required: 1, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		return eBool(e.indexOfValue(call, receiver.(*object.Array), args[0]) != -1)
+	}},
+
+	"indexOf": {params: []string{"value"}, required: 1, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		return &object.Integer{Value: int64(e.indexOfValue(call, receiver.(*object.Array), args[0]))}
+	}},
+}
+
+// callArgument calls the function a method was given, with args
+func (e *Evaluator) callArgument(call *ast.CallExpression, fn object.Object, args ...object.Object) object.Object {
+	return e.applyFunction(argumentNode(call, 0), call, fn, args, nil)
+}
+
+func (e *Evaluator) checkNotFrozen(call *ast.CallExpression, array *object.Array) object.Object {
+	if !array.Frozen {
+		return nil
+	}
+
+	return e.throwErr(
+		call,
+		"This error occurs when changing a value that went through freeze, copy it into a new array or hash instead",
+		"Cannot change a frozen array",
+	)
+}
+
+// lessThan orders numbers with numbers and strings with strings
+func (e *Evaluator) lessThan(call *ast.CallExpression, left, right object.Object) object.Object {
+	l, lok := left.(*object.String)
+	r, rok := right.(*object.String)
+	switch {
+	case lok && rok:
+		return eBool(l.Value < r.Value)
+	case isNumber(left) && isNumber(right):
+		return e.evaluateBinaryOperation(comparison(call, token.LESS), left, right)
+	}
+
+	return e.throwErr(
+		call,
+		"This error occurs when sorting elements that have no natural order between them, pass a less function to sort instead",
+		"Cannot compare '%s' with '%s'",
+		left.Type(),
+		right.Type(),
+	)
+}
+
+// comparison makes the node that comparisons the language makes on its own,
+// like a method's or a match's, are evaluated as. Errors point at node
+func comparison(node ast.Expression, op token.TokenType) *ast.BinaryExpression {
+	return &ast.BinaryExpression{
+		Token:    token.Token{Type: op, Literal: string(op), Line: node.GetLine(), Column: node.GetColumn()},
+		Left:     node,
+		Operator: token.Token{Type: op, Literal: string(op)},
+		Right:    node,
+	}
+}
+
+// indexOfValue finds value the way valuesEqual compares
+func (e *Evaluator) indexOfValue(call *ast.CallExpression, array *object.Array, value object.Object) int {
+	equal := comparison(call, token.EQUAL)
+
+	for i, elem := range array.Elements {
+		if e.valuesEqual(equal, elem, value) {
+			return i
+		}
+	}
+
+	return -1
+}
+
+func isNumber(obj object.Object) bool {
+	switch obj.Type() {
+	case object.NUMBER_OBJECT, object.INTEGER_OBJECT, object.BIGINT_OBJECT, object.DECIMAL_OBJECT, object.INFINITY_OBJECT:
+		return true
+	}
+	return false
+}
+
+/* * [ HASH ] **/
+
+var hashMethods = map[string]*method{
+	"keys": {fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		var keys []object.Object
+		receiver.(*object.Hash).Each(func(pair *object.HashPair) {
+			keys = append(keys, pair.Key)
+		})
+		return &object.Array{Elements: keys}
+	}},
+
+	"values": {fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		var values []object.Object
+		receiver.(*object.Hash).Each(func(pair *object.HashPair) {
+			values = append(values, pair.Value)
+		})
+		return &object.Array{Elements: values}
+	}},
+
+	"contains": {params: []string{"key"}, required: 1, fn: func(e *Evaluator, call *ast.CallExpression, receiver object.Object, args []object.Object) object.Object {
+		key, ok := args[0].(object.Hashable)
+		if !ok {
+			return e.unhashableKey(argumentNode(call, 0), args[0])
+		}
+
+		_, found := receiver.(*object.Hash).Get(key)
+		return eBool(found)
+	}},
+}
+
+// argumentNode is the i-th argument of a call, or the call itself when the
+// argument was filled in some other way
+func argumentNode(call *ast.CallExpression, i int) ast.Node {
+	if i < len(call.Arguments) {
+		return call.Arguments[i]
+	}
+	return call
+}
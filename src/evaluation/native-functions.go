@@ -3,7 +3,6 @@ package evaluation
 import (
 	"bufio"
 	"fmt"
-	"os"
 
 	"github.com/caelondev/monkey/src/ast"
 	"github.com/caelondev/monkey/src/object"
@@ -24,10 +23,13 @@ func (e *Evaluator) NATIVE_LEN_FUNCTION(callNode *ast.CallExpression, args []obj
 	switch arg.Type() {
 	case object.STRING_OBJECT:
 		s, _ := arg.(*object.String)
-		return &object.Number{Value: float64(len(s.Value))}
+		return &object.Integer{Value: int64(len(s.Value))}
 	case object.ARRAY_OBJECT:
 		a, _ := arg.(*object.Array)
-		return &object.Number{Value: float64(len(a.Elements))}
+		return &object.Integer{Value: int64(len(a.Elements))}
+	case object.HASH_OBJECT:
+		h, _ := arg.(*object.Hash)
+		return &object.Integer{Value: int64(len(h.Order))}
 
 	default:
 		return e.throwErr(
@@ -44,17 +46,17 @@ func (e *Evaluator) NATIVE_PRINT_FUNCTION(callNode *ast.CallExpression, args []o
 		if arg.Type() == object.STRING_OBJECT {
 			msg := arg.Inspect()
 			trimmed := msg[1 : len(msg)-1] // Trim ""
-			fmt.Printf("%s", trimmed)
+			fmt.Fprintf(e.stdout, "%s", trimmed)
 		} else {
-			fmt.Printf("%s", arg.Inspect())
+			fmt.Fprintf(e.stdout, "%s", arg.Inspect())
 		}
 
 		if i != len(args)-1 {
-			fmt.Printf(", ")
+			fmt.Fprintf(e.stdout, ", ")
 		}
 	}
 
-	fmt.Println()
+	fmt.Fprintln(e.stdout)
 	return object.NIL
 }
 
@@ -80,9 +82,9 @@ func (e *Evaluator) NATIVE_PROMPT_FUNCTION(callNode *ast.CallExpression, args []
 		)
 	}
 
-	fmt.Print(message.Value)
+	fmt.Fprint(e.stdout, message.Value)
 
-	scanner := bufio.NewScanner(os.Stdin)
+	scanner := bufio.NewScanner(e.stdin)
 	if scanner.Scan() {
 		return &object.String{Value: scanner.Text()}
 	}
@@ -93,3 +95,60 @@ func (e *Evaluator) NATIVE_PROMPT_FUNCTION(callNode *ast.CallExpression, args []
 		"Failed I/O error",
 	)
 }
+
+// NATIVE_FREEZE_FUNCTION makes arrays and hashes immutable, along with every
+// array or hash inside them, and hands back the same value
+func (e *Evaluator) NATIVE_FREEZE_FUNCTION(callNode *ast.CallExpression, args []object.Object) object.Object {
+	if len(args) != 1 {
+		return e.throwErr(
+			callNode,
+			"This error occurs when an argument passed was less than or greater than expected amount",
+			"Expected 1 argument, got %d",
+			len(args),
+		)
+	}
+
+	freeze(args[0])
+	return args[0]
+}
+
+func freeze(obj object.Object) {
+	switch obj := obj.(type) {
+	case *object.Array:
+		if obj.Frozen {
+			return // Also stops on arrays that hold themselves ---
+		}
+		obj.Frozen = true
+		for _, elem := range obj.Elements {
+			freeze(elem)
+		}
+	case *object.Hash:
+		if obj.Frozen {
+			return
+		}
+		obj.Frozen = true
+		obj.Each(func(pair *object.HashPair) {
+			freeze(pair.Value)
+		})
+	case *object.Instance:
+		if obj.Frozen {
+			return
+		}
+		obj.Frozen = true
+		for _, value := range obj.Values {
+			freeze(value)
+		}
+	}
+}
+
+func isFrozen(obj object.Object) bool {
+	switch obj := obj.(type) {
+	case *object.Array:
+		return obj.Frozen
+	case *object.Hash:
+		return obj.Frozen
+	case *object.Instance:
+		return obj.Frozen
+	}
+	return false
+}
@@ -0,0 +1,127 @@
+package evaluation
+
+import (
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/object"
+)
+
+// NOTE: Destructuring works out every binding before any is made, so a ---
+// value that doesn't fit its pattern leaves no variable half assigned ---
+
+type binding struct {
+	name  *ast.Identifier
+	value object.Object
+}
+
+// destructureTargets binds the targets of var and assign. Several targets
+// unpack an array as if they were written [a, b]
+func (e *Evaluator) destructureTargets(node ast.Node, targets []ast.Pattern, value object.Object) ([]binding, object.Object) {
+	if len(targets) == 1 {
+		return e.destructure(targets[0], value, nil)
+	}
+
+	tuple := &ast.ArrayPattern{Elements: targets}
+	return e.unpackArray(node, tuple, value, nil)
+}
+
+func (e *Evaluator) destructure(pattern ast.Pattern, value object.Object, bindings []binding) ([]binding, object.Object) {
+	switch pattern := pattern.(type) {
+	case *ast.Identifier:
+		return append(bindings, binding{pattern, value}), nil
+	case *ast.ArrayPattern:
+		return e.unpackArray(pattern, pattern, value, bindings)
+	case *ast.HashPattern:
+		return e.unpackHash(pattern, value, bindings)
+	}
+
+	return bindings, nil
+}
+
+func (e *Evaluator) unpackArray(node ast.Node, pattern *ast.ArrayPattern, value object.Object, bindings []binding) ([]binding, object.Object) {
+	array, ok := value.(*object.Array)
+	if !ok {
+		return nil, e.throwErr(
+			node,
+			"This error occurs when an array pattern, or several names, are given something that isn't an array",
+			"Cannot unpack a value of type '%s', expected an array",
+			value.Type(),
+		)
+	}
+
+	count := len(pattern.Elements)
+	if len(array.Elements) < count || (pattern.Rest == nil && len(array.Elements) > count) {
+		bound := "exactly"
+		if pattern.Rest != nil {
+			bound = "at least"
+		}
+
+		return nil, e.throwErr(
+			node,
+			"This error occurs when an array has a different number of elements than the pattern has names. Add ...rest to take any extra ones",
+			"Expected %s %d elements to unpack, got %d",
+			bound,
+			count,
+			len(array.Elements),
+		)
+	}
+
+	var err object.Object
+	for i, elem := range pattern.Elements {
+		bindings, err = e.destructure(elem, array.Elements[i], bindings)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if pattern.Rest != nil {
+		rest := make([]object.Object, len(array.Elements)-count)
+		copy(rest, array.Elements[count:])
+		bindings = append(bindings, binding{pattern.Rest, &object.Array{Elements: rest}})
+	}
+
+	return bindings, nil
+}
+
+// unpackHash reads every key the pattern names, a missing key gives nil just
+// like indexing does
+func (e *Evaluator) unpackHash(pattern *ast.HashPattern, value object.Object, bindings []binding) ([]binding, object.Object) {
+	hash, ok := value.(*object.Hash)
+	if !ok {
+		return nil, e.throwErr(
+			pattern,
+			"This error occurs when a hash pattern is given something that isn't a hash",
+			"Cannot unpack a value of type '%s', expected a hash",
+			value.Type(),
+		)
+	}
+
+	taken := make(map[object.HashKey]bool, len(pattern.Keys))
+
+	var err object.Object
+	for i, key := range pattern.Keys {
+		name := &object.String{Value: key.Value}
+		taken[name.HashKey()] = true
+
+		element, ok := hash.Get(name)
+		if !ok {
+			element = object.NIL
+		}
+
+		bindings, err = e.destructure(pattern.Values[i], element, bindings)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	if pattern.Rest != nil {
+		rest := object.NewHash()
+		hash.Each(func(pair *object.HashPair) {
+			if key := pair.Key.(object.Hashable); !taken[key.HashKey()] {
+				rest.Set(key, pair.Value)
+			}
+		})
+		bindings = append(bindings, binding{pattern.Rest, rest})
+	}
+
+	return bindings, nil
+}
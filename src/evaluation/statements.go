@@ -3,6 +3,7 @@ package evaluation
 import (
 	"github.com/caelondev/monkey/src/ast"
 	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/token"
 )
 
 func (e *Evaluator) evaluateBlockStatement(node *ast.BlockStatement, env *object.Environment) object.Object {
@@ -39,20 +40,68 @@ func (e *Evaluator) evaluateIfStatement(node *ast.IfStatement, env *object.Envir
 	}
 }
 
+func (e *Evaluator) evaluateSwitchStatement(node *ast.SwitchStatement, env *object.Environment) object.Object {
+	subject := e.Evaluate(node.Subject, env)
+	if isError(subject) {
+		return subject
+	}
+
+	// default only runs once every case missed, wherever it was written ---
+	var fallback *ast.SwitchCase
+
+	for _, c := range node.Cases {
+		if c.IsDefault() {
+			fallback = c
+			continue
+		}
+
+		for _, value := range c.Values {
+			candidate := e.Evaluate(value, env)
+			if isError(candidate) {
+				return candidate
+			}
+
+			if e.valuesEqual(comparison(value, token.EQUAL), subject, candidate) {
+				return e.Evaluate(c.Body, env)
+			}
+		}
+	}
+
+	if fallback == nil {
+		return object.NIL
+	}
+
+	return e.Evaluate(fallback.Body, env)
+}
+
 func (e *Evaluator) evaluateVariableDeclaration(node *ast.VarStatement, env *object.Environment) object.Object {
+	// A lone name is by far the most common case, it skips destructuring ---
+	if name, ok := plainTarget(node.Targets); ok {
+		if _, exists := localVariable(name, env); exists {
+			return e.redeclaration(node, name)
+		}
+
+		value := e.Evaluate(node.Value, env)
+		if isError(value) {
+			return value
+		}
+
+		if node.Constant {
+			declareConstant(name, env, value)
+		} else {
+			declareVariable(name, env, value)
+		}
+		return value
+	}
+
 	// Check if every assignees are valid ---
 	// Then discard everything if not ---
-	for _, name := range node.Names {
-		if !env.DoesExist(name.Value) {
-			continue
+	for _, target := range node.Targets {
+		for _, name := range ast.PatternNames(target) {
+			if _, exists := localVariable(name, env); exists {
+				return e.redeclaration(node, name)
+			}
 		}
-
-		return e.throwErr(
-			node.Value,
-			"This error occurs when a variable that is already declared was redeclared again in the same scope",
-			"Cannot declare '%s' as it already exists",
-			name.Value,
-		)
 	}
 
 	value := e.Evaluate(node.Value, env)
@@ -60,33 +109,68 @@ func (e *Evaluator) evaluateVariableDeclaration(node *ast.VarStatement, env *obj
 		return value
 	}
 
-	for _, name := range node.Names {
-		env.Set(name.Value, value)
+	// var a, b; leaves every name nil instead of unpacking nil ---
+	if ast.IsImplicitNil(node.Value) {
+		for _, target := range node.Targets {
+			declareVariable(target.(*ast.Identifier), env, value)
+		}
+		return value
+	}
+
+	bindings, err := e.destructureTargets(node, node.Targets, value)
+	if err != nil {
+		return err
+	}
+
+	for _, bound := range bindings {
+		if node.Constant {
+			declareConstant(bound.name, env, bound.value)
+		} else {
+			declareVariable(bound.name, env, bound.value)
+		}
 	}
 
 	return value
 }
 
 func (e *Evaluator) evaluateBatchAssignmentStatement(node *ast.BatchAssignmentStatement, env *object.Environment) object.Object {
+	// A lone name skips destructuring, like it does in var ---
+	if assignee, ok := plainTarget(node.Assignees); ok {
+		if err := e.checkAssignee(assignee, env); err != nil {
+			return err
+		}
+
+		newValue := e.Evaluate(node.NewValue, env)
+		if isError(newValue) {
+			return newValue
+		}
+
+		assignVariable(assignee, env, newValue)
+		return newValue
+	}
+
 	// Check if every assignees are valid ---
 	// Then discard everything if not ---
-	for _, assignee := range node.Assignees {
-		exists := env.DoesExist(assignee.Value)
-
-		if !exists {
-			return e.throwErr(
-				assignee,
-				"This error occurs when the assignee variable doesnt exist",
-				"Cannot resolve variable '%s'",
-				assignee.Value,
-			)
+	for _, target := range node.Assignees {
+		for _, assignee := range ast.PatternNames(target) {
+			if err := e.checkAssignee(assignee, env); err != nil {
+				return err
+			}
 		}
 	}
 
 	newValue := e.Evaluate(node.NewValue, env)
+	if isError(newValue) {
+		return newValue
+	}
+
+	bindings, err := e.destructureTargets(node, node.Assignees, newValue)
+	if err != nil {
+		return err
+	}
 
-	for _, assignee := range node.Assignees {
-		env.Set(assignee.Value, newValue)
+	for _, bound := range bindings {
+		assignVariable(bound.name, env, bound.value)
 	}
 
 	return newValue
@@ -106,9 +190,61 @@ func (e *Evaluator) evaluateFunctionDeclaration(node *ast.FunctionDeclarationSta
 		Parameters: node.Parameters,
 		Name:       node.Name,
 		Body:       node.Body,
+		Slots:      node.Slots,
 		Scope:      env,
 	}
 
-	value := env.Declare(node.Name.Value, function)
-	return value
+	declareVariable(node.Name, env, function)
+	return function
+}
+
+// plainTarget gives the name when targets is a single one, not a pattern
+func plainTarget(targets []ast.Pattern) (*ast.Identifier, bool) {
+	if len(targets) != 1 {
+		return nil, false
+	}
+
+	name, ok := targets[0].(*ast.Identifier)
+	return name, ok
+}
+
+func (e *Evaluator) redeclaration(node *ast.VarStatement, name *ast.Identifier) object.Object {
+	return e.throwErr(
+		node.Value,
+		"This error occurs when a variable that is already declared was redeclared again in the same scope",
+		"Cannot declare '%s' as it already exists",
+		name.Value,
+	)
+}
+
+// checkAssignee makes sure assign can write to the variable
+func (e *Evaluator) checkAssignee(assignee *ast.Identifier, env *object.Environment) object.Object {
+	if _, exists := lookupVariable(assignee, env); !exists {
+		return e.throwErr(
+			assignee,
+			"This error occurs when the assignee variable doesnt exist",
+			"Cannot resolve variable '%s'",
+			assignee.Value,
+		)
+	}
+
+	if isConstant(assignee, env) {
+		return e.constantAssignment(assignee, assignee)
+	}
+
+	return nil
+}
+
+// localVariable only looks in the current scope, redeclaring a variable
+// from an outer scope is allowed
+func localVariable(node *ast.Identifier, env *object.Environment) (object.Object, bool) {
+	if node.Binding != nil {
+		return env.GetAt(0, node.Binding.Slot)
+	}
+
+	if !env.DoesExist(node.Value) {
+		return nil, false
+	}
+
+	return env.Get(node.Value)
 }
@@ -0,0 +1,146 @@
+package evaluation
+
+import (
+	"strings"
+
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/object"
+)
+
+// NOTE: Calling a struct builds an instance. Without an init method ---
+// the fields are the constructor's parameters, defaults and named ---
+// arguments included. With one, every field starts at its default ---
+// or nil and init gets the arguments. Methods are bound to their ---
+// instance when they're read, through a scope that only holds self ---
+
+func (e *Evaluator) evaluateStructStatement(node *ast.StructStatement, env *object.Environment) object.Object {
+	structure := &object.Struct{
+		Name:    node.Name.Value,
+		Fields:  node.Fields,
+		Methods: make(map[string]*ast.FunctionDeclarationStatement, len(node.Methods)),
+		Scope:   env,
+	}
+
+	for _, method := range node.Methods {
+		structure.Methods[method.Name.Value] = method
+	}
+
+	declareVariable(node.Name, env, structure)
+	return structure
+}
+
+func (e *Evaluator) construct(
+	callNode *ast.CallExpression,
+	structure *object.Struct,
+	args []object.Object,
+	named []object.NamedArgument,
+) object.Object {
+	instance := &object.Instance{Struct: structure, Values: make([]object.Object, len(structure.Fields))}
+
+	init, ok := structure.Methods["init"]
+	if !ok {
+		constructor := &object.Function{Parameters: structure.Fields, Scope: structure.Scope}
+
+		env, err := e.extendFunctionEnv(constructor, callNode, args, named)
+		if err != nil {
+			return err
+		}
+
+		for i, field := range structure.Fields {
+			instance.Values[i], _ = env.Get(field.Ident().Value)
+		}
+		return instance
+	}
+
+	// Defaults see the fields before them, like parameters do ---
+	env := object.NewEnvironment(structure.Scope)
+	for i, field := range structure.Fields {
+		var value object.Object = object.NIL
+		if field.Default != nil {
+			value = e.Evaluate(field.Default, env)
+			if isError(value) {
+				return value
+			}
+		}
+
+		declareVariable(field.Ident(), env, value)
+		instance.Values[i] = value
+	}
+
+	result := e.applyFunction(callNode.Function, callNode, bindMethod(instance, init), args, named)
+	if isError(result) {
+		return result
+	}
+
+	return instance
+}
+
+// bindMethod makes the function a method runs as, with self in scope
+func bindMethod(instance *object.Instance, decl *ast.FunctionDeclarationStatement) *object.Function {
+	env := object.NewEnvironment(instance.Struct.Scope)
+	env.Declare("self", instance)
+
+	return &object.Function{
+		Parameters: decl.Parameters,
+		Name:       decl.Name,
+		Body:       decl.Body,
+		Slots:      decl.Slots,
+		Scope:      env,
+	}
+}
+
+func (e *Evaluator) instanceMember(node *ast.MemberExpression, instance *object.Instance) object.Object {
+	if i := instance.Struct.FieldIndex(node.Property); i != -1 {
+		return instance.Values[i]
+	}
+
+	if decl, ok := instance.Struct.Methods[node.Property]; ok {
+		return bindMethod(instance, decl)
+	}
+
+	return e.throwErr(
+		node,
+		"This error occurs when a struct declares no field or method of that name",
+		"'%s' has no field or method '%s'",
+		instance.Struct.Name,
+		node.Property,
+	)
+}
+
+// setMemberValue stores value in a field of an instance, in place. Only
+// declared fields exist, assigning can't add one
+func (e *Evaluator) setMemberValue(node *ast.MemberExpression, target, value object.Object) object.Object {
+	instance, ok := target.(*object.Instance)
+	if !ok {
+		return e.throwErr(
+			node,
+			"This error occurs when assigning to a member of something that isn't a struct instance",
+			"Cannot assign to member '%s' of type '%s'",
+			node.Property,
+			target.Type(),
+		)
+	}
+
+	if instance.Frozen {
+		return e.throwErr(
+			node,
+			"This error occurs when changing a value that went through freeze, copy it into a new instance instead",
+			"Cannot change a frozen %s",
+			strings.ToLower(string(target.Type())),
+		)
+	}
+
+	i := instance.Struct.FieldIndex(node.Property)
+	if i == -1 {
+		return e.throwErr(
+			node,
+			"This error occurs when assigning a field the struct doesn't declare, add it to the struct instead",
+			"Cannot add field '%s' to '%s', it only has the fields it declares",
+			node.Property,
+			instance.Struct.Name,
+		)
+	}
+
+	instance.Values[i] = value
+	return value
+}
@@ -0,0 +1,642 @@
+package format
+
+import (
+	"errors"
+	"strings"
+
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/lexer"
+	"github.com/caelondev/monkey/src/parser"
+	"github.com/caelondev/monkey/src/token"
+)
+
+// NOTE: ast.Node.String() can't be used here, it drops comments and ---
+// wraps everything in parentheses. This printer rebuilds the source ---
+// from the AST and puts the lexer's comment trivia back by position ---
+
+const (
+	indentWidth = 4
+	maxWidth    = 80
+)
+
+type comment struct {
+	token.Comment
+	trailing bool // Shares its line with code before it ---
+}
+
+type printer struct {
+	out      *strings.Builder
+	indent   int
+	lines    []string
+	comments []comment
+	next     int // Next comment waiting to be printed ---
+}
+
+// Source returns the canonical formatting of a Monkey program. Programs that
+// don't parse are refused, formatting them would lose code
+func Source(source string) (string, error) {
+	p := parser.New(lexer.New(source))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		return "", errors.New(strings.Join(p.Errors(), "\n"))
+	}
+
+	pr := &printer{
+		out:      &strings.Builder{},
+		lines:    strings.Split(source, "\n"),
+		comments: collectComments(source),
+	}
+
+	pr.statements(program.Statements)
+	pr.commentsBefore(^uint(0))
+
+	return pr.out.String(), nil
+}
+
+// Program prints an AST that has no source behind it, like one rewritten by
+// the optimizer. There are no comments or blank lines to keep
+func Program(program *ast.Program) string {
+	pr := &printer{out: &strings.Builder{}}
+	pr.statements(program.Statements)
+
+	return pr.out.String()
+}
+
+func collectComments(source string) []comment {
+	var comments []comment
+	var previousLine uint
+
+	l := lexer.New(source)
+
+	for {
+		tok := l.NextToken()
+
+		for _, c := range tok.Trivia {
+			comments = append(comments, comment{Comment: c, trailing: c.Line == previousLine})
+			previousLine = c.Line + uint(strings.Count(c.Text, "\n"))
+		}
+
+		if tok.Type == token.EOF {
+			return comments
+		}
+
+		previousLine = tok.Line
+	}
+}
+
+/*
+* [ OUTPUT ]
+**/
+
+func (p *printer) line(text string) {
+	p.out.WriteString(strings.Repeat(" ", p.indent*indentWidth))
+	p.out.WriteString(text)
+	p.out.WriteString("\n")
+}
+
+// commentsBefore prints every pending comment that starts before the line
+func (p *printer) commentsBefore(line uint) {
+	for p.next < len(p.comments) && p.comments[p.next].Line < line {
+		c := p.comments[p.next]
+		p.next++
+
+		if c.trailing && p.out.Len() != 0 {
+			// Glue it back onto the end of the line it came from ---
+			text := strings.TrimSuffix(p.out.String(), "\n")
+			p.out.Reset()
+			p.out.WriteString(text + " " + c.Text + "\n")
+			continue
+		}
+
+		if p.blankLineBefore(c.Line) {
+			p.out.WriteString("\n")
+		}
+
+		for _, text := range strings.Split(c.Text, "\n") {
+			p.line(strings.TrimRight(text, " \t\r"))
+		}
+	}
+}
+
+// blankLineBefore keeps one empty line wherever the source had at least one
+func (p *printer) blankLineBefore(line uint) bool {
+	if p.out.Len() == 0 || line < 2 || int(line) > len(p.lines)+1 {
+		return false
+	}
+
+	if strings.HasSuffix(p.out.String(), "{\n") {
+		return false
+	}
+
+	return strings.TrimSpace(p.lines[line-2]) == ""
+}
+
+/*
+* [ STATEMENTS ]
+**/
+
+func (p *printer) statements(statements []ast.Statement) {
+	for _, stmt := range statements {
+		p.commentsBefore(stmt.GetLine())
+
+		if p.blankLineBefore(stmt.GetLine()) {
+			p.out.WriteString("\n")
+		}
+
+		p.statement(stmt)
+	}
+}
+
+func (p *printer) statement(node ast.Statement) {
+	switch node := node.(type) {
+	case *ast.VarStatement:
+		keyword, names := "var ", p.patterns(node.Targets)
+		if node.Constant {
+			keyword = "const "
+		}
+
+		if ast.IsImplicitNil(node.Value) && !node.Constant {
+			p.line(keyword + names + ";")
+		} else {
+			p.line(keyword + names + " = " + p.expression(node.Value, parser.LOWEST) + ";")
+		}
+
+	case *ast.BatchAssignmentStatement:
+		p.line("assign " + p.patterns(node.Assignees) + " = " + p.expression(node.NewValue, parser.LOWEST) + ";")
+
+	case *ast.ReturnStatement:
+		if node.ReturnValue == nil {
+			p.line("return;")
+		} else {
+			p.line("return " + p.expression(node.ReturnValue, parser.LOWEST) + ";")
+		}
+
+	case *ast.ExpressionStatement:
+		p.line(p.expression(node.Expression, parser.LOWEST) + ";")
+
+	case *ast.FunctionDeclarationStatement:
+		p.line("fn " + node.Name.Value + "(" + p.parameters(node.Parameters) + ") {")
+		p.block(node.Body)
+		p.line("}")
+
+	case *ast.StructStatement:
+		p.structStatement(node)
+
+	case *ast.EnumStatement:
+		p.enumStatement(node)
+
+	case *ast.IfStatement:
+		p.ifStatement(node, "")
+
+	case *ast.SwitchStatement:
+		p.switchStatement(node)
+
+	case *ast.BlockStatement:
+		p.line("{")
+		p.block(node)
+		p.line("}")
+	}
+}
+
+// structStatement puts every field on one line, then the methods
+func (p *printer) structStatement(node *ast.StructStatement) {
+	p.line("struct " + node.Name.Value + " {")
+	p.indent++
+
+	if len(node.Fields) != 0 {
+		p.commentsBefore(node.Fields[0].GetLine())
+		p.line(p.parameters(node.Fields) + ";")
+	}
+
+	for _, method := range node.Methods {
+		p.commentsBefore(method.GetLine())
+
+		if p.blankLineBefore(method.GetLine()) {
+			p.out.WriteString("\n")
+		}
+
+		p.statement(method)
+	}
+
+	p.commentsBefore(node.EndToken.Line)
+	p.indent--
+	p.line("}")
+}
+
+// enumStatement puts every variant on a line of its own
+func (p *printer) enumStatement(node *ast.EnumStatement) {
+	p.line("enum " + node.Name.Value + " {")
+	p.indent++
+
+	for i, variant := range node.Variants {
+		p.commentsBefore(variant.GetLine())
+
+		if i != len(node.Variants)-1 {
+			p.line(variant.String() + ",")
+		} else {
+			p.line(variant.String())
+		}
+	}
+
+	p.commentsBefore(node.EndToken.Line)
+	p.indent--
+	p.line("}")
+}
+
+func (p *printer) ifStatement(node *ast.IfStatement, prefix string) {
+	p.line(prefix + "if (" + p.expression(node.Condition, parser.LOWEST) + ") {")
+	p.branch(node.Consequence)
+
+	switch alt := node.Alternative.(type) {
+	case nil:
+		p.line("}")
+	case *ast.IfStatement:
+		p.ifStatement(alt, "} else ")
+	default:
+		p.line("} else {")
+		p.branch(alt)
+		p.line("}")
+	}
+}
+
+// switchStatement indents the cases inside the switch and their bodies
+// inside the cases
+func (p *printer) switchStatement(node *ast.SwitchStatement) {
+	p.line("switch (" + p.expression(node.Subject, parser.LOWEST) + ") {")
+	p.indent++
+
+	for _, c := range node.Cases {
+		p.commentsBefore(c.GetLine())
+
+		if p.blankLineBefore(c.GetLine()) {
+			p.out.WriteString("\n")
+		}
+
+		if c.IsDefault() {
+			p.line("default:")
+		} else {
+			values := make([]string, len(c.Values))
+			for i, value := range c.Values {
+				values[i] = p.expression(value, parser.LOWEST)
+			}
+			p.line("case " + strings.Join(values, ", ") + ":")
+		}
+
+		p.indent++
+		p.statements(c.Body.Statements)
+		p.indent--
+	}
+
+	p.commentsBefore(node.EndToken.Line)
+	p.indent--
+	p.line("}")
+}
+
+// branch always prints braces, one-line if bodies become blocks
+func (p *printer) branch(node ast.Statement) {
+	if block, ok := node.(*ast.BlockStatement); ok {
+		p.block(block)
+		return
+	}
+
+	p.indent++
+	p.commentsBefore(node.GetLine())
+	p.statement(node)
+	p.indent--
+}
+
+func (p *printer) block(block *ast.BlockStatement) {
+	p.indent++
+	p.statements(block.Statements)
+	p.commentsBefore(block.EndToken.Line)
+	p.indent--
+}
+
+/*
+* [ EXPRESSIONS ]
+**/
+
+func (p *printer) expression(node ast.Expression, parentPrecedence int) string {
+	text := p.bareExpression(node)
+
+	if precedenceOf(node) < parentPrecedence {
+		return "(" + text + ")"
+	}
+
+	return text
+}
+
+func (p *printer) bareExpression(node ast.Expression) string {
+	switch node := node.(type) {
+	case *ast.Identifier:
+		return node.Value
+	case *ast.NumberLiteral, *ast.IntegerLiteral, *ast.BigIntLiteral, *ast.DecimalLiteral:
+		return node.TokenLiteral()
+	case *ast.StringLiteral:
+		if strings.Contains(node.Value, "\"") {
+			return "'" + node.Value + "'"
+		}
+		return "\"" + node.Value + "\""
+	case *ast.BooleanExpression:
+		return node.Token.Literal
+	case *ast.NilLiteral:
+		return "nil"
+	case *ast.NaNLiteral:
+		return "NaN"
+	case *ast.InfinityLiteral:
+		return "Inf"
+
+	case *ast.UnaryExpression:
+		right := p.expression(node.Right, parser.UNARY)
+		if node.Operator.Type == token.MINUS && strings.HasPrefix(right, "-") {
+			right = "(" + right + ")" // - -x would read back as --x ---
+		}
+		return node.Operator.Literal + right
+
+	case *ast.UpdateExpression:
+		if node.Prefix {
+			return node.Operator.Literal + p.expression(node.Target, parser.UNARY)
+		}
+		return p.expression(node.Target, parser.CALL) + node.Operator.Literal
+
+	case *ast.BinaryExpression:
+		op := node.Operator.Type
+		pre := parser.OperatorPrecedence(op)
+
+		// ^ is right associative, everything else groups to the left ---
+		leftPre, rightPre := pre, pre+1
+		if op == token.CARET {
+			leftPre, rightPre = pre+1, pre
+		}
+
+		return p.expression(node.Left, leftPre) + " " + node.Operator.Literal + " " + p.expression(node.Right, rightPre)
+
+	case *ast.TernaryExpression:
+		return p.expression(node.Consequence, parser.TERNARY) +
+			" if " + p.expression(node.Condition, parser.TERNARY+1) +
+			" else " + p.expression(node.Alternative, parser.TERNARY+1)
+
+	case *ast.AssignmentExpression:
+		return p.expression(node.Assignee, parser.CALL) + " " + node.Token.Literal + " " + p.expression(node.NewValue, parser.ASSIGNMENT+1)
+
+	case *ast.CallExpression:
+		open := "("
+		if node.Optional {
+			open = "?.("
+		}
+		return p.expression(node.Function, parser.CALL) + p.list(open, node.Arguments, ")")
+
+	case *ast.PipeExpression:
+		value := p.expression(node.Value(), parser.PIPELINE)
+		if node.Bare {
+			return value + " |> " + p.expression(node.Call.Function, parser.PIPELINE+1)
+		}
+
+		open := "("
+		if node.Call.Optional {
+			open = "?.("
+		}
+		return value + " |> " + p.expression(node.Call.Function, parser.CALL) + p.list(open, node.Arguments(), ")")
+
+	case *ast.IndexExpression:
+		return p.expression(node.Target, parser.CALL) + node.Token.Literal + p.expression(node.Index, parser.LOWEST) + "]"
+
+	case *ast.MemberExpression:
+		return p.expression(node.Target, parser.CALL) + node.Token.Literal + node.Property
+
+	case *ast.SliceExpression:
+		bound := func(expr ast.Expression) string {
+			if expr == nil {
+				return ""
+			}
+			return p.expression(expr, parser.LOWEST)
+		}
+
+		bounds := bound(node.Start) + ":" + bound(node.End)
+		if node.Step != nil {
+			bounds += ":" + bound(node.Step)
+		}
+		return p.expression(node.Target, parser.CALL) + node.Token.Literal + bounds + "]"
+
+	case *ast.ArrayLiteral:
+		return p.list("[", node.Elements, "]")
+
+	case *ast.SpreadExpression:
+		return "..." + p.expression(node.Value, parser.LOWEST)
+
+	case *ast.NamedArgument:
+		return node.Name + ": " + p.expression(node.Value, parser.LOWEST)
+
+	case *ast.HashLiteral:
+		return p.items("{", len(node.Keys), func(i int) string {
+			return p.expression(node.Keys[i], parser.LOWEST) + ": " + p.expression(node.Values[i], parser.LOWEST)
+		}, "}")
+
+	case *ast.FunctionLiteral:
+		return p.functionLiteral(node)
+
+	case *ast.MatchExpression:
+		return p.matchExpression(node)
+	}
+
+	return node.String()
+}
+
+func (p *printer) functionLiteral(node *ast.FunctionLiteral) string {
+	// Render the body with the real printer so its comments come along ---
+	saved := p.out
+	p.out = &strings.Builder{}
+
+	p.block(node.Body)
+	body := p.out.String()
+	p.out = saved
+
+	closing := strings.Repeat(" ", p.indent*indentWidth) + "}"
+	return "fn(" + p.parameters(node.Parameters) + ") {\n" + body + closing
+}
+
+// matchExpression puts every arm on a line of its own
+func (p *printer) matchExpression(node *ast.MatchExpression) string {
+	subject := p.expression(node.Subject, parser.LOWEST)
+
+	// Render the arms with the real printer so their comments come along ---
+	saved := p.out
+	p.out = &strings.Builder{}
+	p.indent++
+
+	for i, arm := range node.Arms {
+		p.commentsBefore(arm.GetLine())
+
+		text := p.pattern(arm.Pattern)
+		if arm.Guard != nil {
+			text += " if " + p.expression(arm.Guard, parser.LOWEST)
+		}
+		text += " => " + p.expression(arm.Body, parser.LOWEST)
+
+		if i != len(node.Arms)-1 {
+			text += ","
+		}
+		p.line(text)
+	}
+
+	p.commentsBefore(node.EndToken.Line)
+	p.indent--
+
+	arms := p.out.String()
+	p.out = saved
+
+	closing := strings.Repeat(" ", p.indent*indentWidth) + "}"
+	return "match " + subject + " {\n" + arms + closing
+}
+
+// list prints call arguments and array elements, one per line when they
+// don't fit within the line width
+func (p *printer) list(open string, exprs []ast.Expression, close string) string {
+	return p.items(open, len(exprs), func(i int) string {
+		return p.expression(exprs[i], parser.LOWEST)
+	}, close)
+}
+
+// items is list for anything made of count parts, render prints the i-th
+func (p *printer) items(open string, count int, render func(i int) string, close string) string {
+	saved := p.next
+
+	parts := make([]string, count)
+	for i := range parts {
+		parts[i] = render(i)
+	}
+
+	inline := open + strings.Join(parts, ", ") + close
+	if strings.Contains(inline, "\n") || p.indent*indentWidth+len(inline) <= maxWidth {
+		return inline
+	}
+
+	// Re-render one level deeper, from the same comment ---
+	p.next = saved
+	p.indent++
+	itemIndent := strings.Repeat(" ", p.indent*indentWidth)
+
+	var out strings.Builder
+	out.WriteString(open + "\n")
+	for i := range count {
+		out.WriteString(itemIndent + render(i))
+		if i != count-1 {
+			out.WriteString(",") // The parser has no trailing commas ---
+		}
+		out.WriteString("\n")
+	}
+
+	p.indent--
+	out.WriteString(strings.Repeat(" ", p.indent*indentWidth) + close)
+
+	return out.String()
+}
+
+/*
+* [ HELPERS ]
+**/
+
+func precedenceOf(node ast.Expression) int {
+	switch node := node.(type) {
+	case *ast.BinaryExpression:
+		return parser.OperatorPrecedence(node.Operator.Type)
+	case *ast.TernaryExpression:
+		return parser.TERNARY
+	case *ast.PipeExpression:
+		return parser.PIPELINE
+	case *ast.AssignmentExpression:
+		return parser.ASSIGNMENT
+	case *ast.UnaryExpression:
+		return parser.UNARY
+	case *ast.UpdateExpression:
+		if node.Prefix {
+			return parser.UNARY
+		}
+		return parser.CALL
+	case *ast.CallExpression, *ast.IndexExpression, *ast.SliceExpression, *ast.MemberExpression:
+		return parser.CALL
+	default:
+		return parser.CALL + 1
+	}
+}
+
+func (p *printer) parameters(params []*ast.Parameter) string {
+	parts := make([]string, len(params))
+	for i, param := range params {
+		switch {
+		case param.Rest:
+			parts[i] = "..." + p.pattern(param.Target)
+		case param.Default != nil:
+			parts[i] = p.pattern(param.Target) + " = " + p.expression(param.Default, parser.LOWEST)
+		default:
+			parts[i] = p.pattern(param.Target)
+		}
+	}
+
+	return strings.Join(parts, ", ")
+}
+
+func (p *printer) patterns(patterns []ast.Pattern) string {
+	parts := make([]string, len(patterns))
+	for i, pattern := range patterns {
+		parts[i] = p.pattern(pattern)
+	}
+	return strings.Join(parts, ", ")
+}
+
+func (p *printer) pattern(node ast.Pattern) string {
+	var parts []string
+
+	switch node := node.(type) {
+	case *ast.Identifier:
+		return node.Value
+
+	case *ast.ArrayPattern:
+		for _, elem := range node.Elements {
+			parts = append(parts, p.pattern(elem))
+		}
+		if node.Rest != nil {
+			parts = append(parts, "..."+node.Rest.Value)
+		}
+		return "[" + strings.Join(parts, ", ") + "]"
+
+	case *ast.HashPattern:
+		for i, key := range node.Keys {
+			switch {
+			case node.IsShorthand(i):
+				parts = append(parts, key.Value)
+			case key.Token.Type == token.IDENTIFIER:
+				parts = append(parts, key.Value+": "+p.pattern(node.Values[i]))
+			default:
+				parts = append(parts, p.expression(key, parser.LOWEST)+": "+p.pattern(node.Values[i]))
+			}
+		}
+		if node.Rest != nil {
+			parts = append(parts, "..."+node.Rest.Value)
+		}
+		return "{" + strings.Join(parts, ", ") + "}"
+
+	case *ast.WildcardPattern:
+		return "_"
+
+	case *ast.LiteralPattern:
+		return p.expression(node.Value, parser.LOWEST)
+
+	case *ast.ConstructorPattern:
+		name := p.expression(node.Type, parser.LOWEST)
+		if !node.HasParentheses() {
+			return name
+		}
+
+		for _, elem := range node.Elements {
+			parts = append(parts, p.pattern(elem))
+		}
+		for i, key := range node.Keys {
+			parts = append(parts, key.Value+": "+p.pattern(node.Values[i]))
+		}
+		return name + "(" + strings.Join(parts, ", ") + ")"
+	}
+
+	return node.String()
+}
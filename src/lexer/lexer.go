@@ -9,6 +9,7 @@ type Lexer struct {
 	currentChar     byte
 	line            uint
 	column          uint
+	previous        token.Token // The last token handed out, // after a value divides ---
 }
 
 func New(source string) *Lexer {
@@ -23,11 +24,12 @@ func New(source string) *Lexer {
 
 func (l *Lexer) NextToken() token.Token {
 	var tok token.Token
+	var trivia []token.Comment
 
 	for {
 		l.skipWhitespace()
-		if l.currentChar == '/' && (l.peekChar() == '/' || l.peekChar() == '*') {
-			l.skipComments()
+		if l.currentChar == '/' && (l.peekChar() == '*' || l.peekChar() == '/' && !l.afterOperand()) {
+			trivia = append(trivia, l.readComment())
 		} else {
 			break
 		}
@@ -41,32 +43,112 @@ func (l *Lexer) NextToken() token.Token {
 	case ';':
 		tok = l.newTokenWithPos(token.SEMICOLON, l.currentChar, startLine, startColumn)
 		l.readChar()
+	case ':':
+		tok = l.newTokenWithPos(token.COLON, l.currentChar, startLine, startColumn)
+		l.readChar()
+	case '.':
+		if l.peekChar() == '.' && l.currentPosition+1 < len(l.source) && l.source[l.currentPosition+1] == '.' {
+			l.readChar()
+			l.readChar()
+			tok = token.Token{Type: token.ELLIPSIS, Literal: "...", Line: startLine, Column: startColumn}
+		} else {
+			tok = l.newTokenWithPos(token.DOT, l.currentChar, startLine, startColumn)
+		}
+		l.readChar()
 	case '^':
-		tok = l.newTokenWithPos(token.CARET, l.currentChar, startLine, startColumn)
+		tok = l.newCompound(token.CARET, token.CARET_ASSIGN, startLine, startColumn)
 		l.readChar()
 	case '+':
-		tok = l.newTokenWithPos(token.PLUS, l.currentChar, startLine, startColumn)
+		if l.peekChar() == '+' && l.isUpdate() {
+			l.readChar()
+			tok = token.Token{Type: token.INCREMENT, Literal: "++", Line: startLine, Column: startColumn}
+		} else {
+			tok = l.newCompound(token.PLUS, token.PLUS_ASSIGN, startLine, startColumn)
+		}
 		l.readChar()
 	case '-':
-		tok = l.newTokenWithPos(token.MINUS, l.currentChar, startLine, startColumn)
+		if l.peekChar() == '-' && l.isUpdate() {
+			l.readChar()
+			tok = token.Token{Type: token.DECREMENT, Literal: "--", Line: startLine, Column: startColumn}
+		} else {
+			tok = l.newCompound(token.MINUS, token.MINUS_ASSIGN, startLine, startColumn)
+		}
 		l.readChar()
 	case '*':
-		tok = l.newTokenWithPos(token.STAR, l.currentChar, startLine, startColumn)
+		tok = l.newCompound(token.STAR, token.STAR_ASSIGN, startLine, startColumn)
 		l.readChar()
 	case '/':
-		tok = l.newTokenWithPos(token.SLASH, l.currentChar, startLine, startColumn)
+		// Past the comment check, a second / can only be floor division ---
+		if l.peekChar() == '/' {
+			l.readChar()
+			tok = token.Token{Type: token.FLOOR_DIV, Literal: "//", Line: startLine, Column: startColumn}
+		} else {
+			tok = l.newCompound(token.SLASH, token.SLASH_ASSIGN, startLine, startColumn)
+		}
+		l.readChar()
+	case '%':
+		tok = l.newTokenWithPos(token.PERCENT, l.currentChar, startLine, startColumn)
+		l.readChar()
+	case '~':
+		if l.peekChar() == '/' {
+			l.readChar()
+			tok = token.Token{Type: token.FLOOR_DIV, Literal: "~/", Line: startLine, Column: startColumn}
+		} else {
+			tok = l.newTokenWithPos(token.TILDE, l.currentChar, startLine, startColumn)
+		}
+		l.readChar()
+	case '&':
+		tok = l.newTokenWithPos(token.AMPERSAND, l.currentChar, startLine, startColumn)
+		l.readChar()
+	case '|':
+		if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.Token{Type: token.PIPELINE, Literal: "|>", Line: startLine, Column: startColumn}
+		} else {
+			tok = l.newTokenWithPos(token.PIPE, l.currentChar, startLine, startColumn)
+		}
 		l.readChar()
 	case '!':
 		tok = l.newCompound(token.BANG, token.NOT_EQUAL, startLine, startColumn)
 		l.readChar()
+	case '?':
+		switch l.peekChar() {
+		case '?':
+			l.readChar()
+			tok = token.Token{Type: token.NULLISH, Literal: "??", Line: startLine, Column: startColumn}
+		case '.':
+			l.readChar()
+			tok = token.Token{Type: token.QUESTION_DOT, Literal: "?.", Line: startLine, Column: startColumn}
+		case '[':
+			l.readChar()
+			tok = token.Token{Type: token.QUESTION_BRACKET, Literal: "?[", Line: startLine, Column: startColumn}
+		default:
+			tok = l.newTokenWithPos(token.ILLEGAL, l.currentChar, startLine, startColumn)
+		}
+		l.readChar()
 	case '=':
-		tok = l.newCompound(token.ASSIGNMENT, token.EQUAL, startLine, startColumn)
+		if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.Token{Type: token.FAT_ARROW, Literal: "=>", Line: startLine, Column: startColumn}
+		} else {
+			tok = l.newCompound(token.ASSIGNMENT, token.EQUAL, startLine, startColumn)
+		}
 		l.readChar()
 	case '<':
-		tok = l.newCompound(token.LESS, token.LESS_EQUAL, startLine, startColumn)
+		if l.peekChar() == '<' {
+			l.readChar()
+			tok = token.Token{Type: token.SHIFT_LEFT, Literal: "<<", Line: startLine, Column: startColumn}
+		} else {
+			tok = l.newCompound(token.LESS, token.LESS_EQUAL, startLine, startColumn)
+		}
 		l.readChar()
 	case '>':
-		tok = l.newCompound(token.GREATER, token.GREATER_EQUAL, startLine, startColumn)
+		if l.peekChar() == '>' {
+			l.readChar()
+			tok = token.Token{Type: token.SHIFT_RIGHT, Literal: ">>", Line: startLine, Column: startColumn}
+		} else {
+			tok = l.newCompound(token.GREATER, token.GREATER_EQUAL, startLine, startColumn)
+		}
 		l.readChar()
 	case ',':
 		tok = l.newTokenWithPos(token.COMMA, l.currentChar, startLine, startColumn)
@@ -119,9 +201,59 @@ func (l *Lexer) NextToken() token.Token {
 		}
 	}
 
+	tok.Trivia = trivia
+	l.previous = tok
 	return tok
 }
 
+// isUpdate tells whether the ++ or -- at the current position updates a
+// variable. After a value it only does when the value may be assignable and
+// no operand follows, so 5--3 and a--b stay a subtraction of a negation.
+// Before a value it has to be followed by a name, --3 negates twice
+func (l *Lexer) isUpdate() bool {
+	next := l.currentPosition + 1
+	for next < len(l.source) && (l.source[next] == ' ' || l.source[next] == '\t') {
+		next++
+	}
+
+	var following byte
+	if next < len(l.source) {
+		following = l.source[next]
+	}
+
+	if !l.afterOperand() {
+		return isLetter(following)
+	}
+
+	switch l.previous.Type {
+	case token.IDENTIFIER, token.RIGHT_BRACKET, token.RIGHT_PARENTHESIS:
+	default:
+		return false
+	}
+
+	switch {
+	case isAlphanumeric(following), following == '(', following == '[', following == '"', following == '\'':
+		return false
+	}
+	return true
+}
+
+// afterOperand tells whether the last token ended a value on the current
+// line. // there is floor division, anywhere else it starts a comment
+func (l *Lexer) afterOperand() bool {
+	if l.previous.Line != l.line {
+		return false
+	}
+
+	switch l.previous.Type {
+	case token.IDENTIFIER, token.NUMBER, token.STRING, token.RIGHT_PARENTHESIS, token.RIGHT_BRACKET,
+		token.TRUE, token.FALSE, token.NIL, token.INFINITY, token.NOT_A_NUMBER:
+		return true
+	}
+
+	return false
+}
+
 func (l *Lexer) readString(terminator byte, line, column uint) token.Token {
 	// consume opening quote
 	l.readChar()
@@ -148,11 +280,25 @@ func (l *Lexer) readString(terminator byte, line, column uint) token.Token {
 	}
 }
 
+// readNumber reads an integer, or a float when a fraction follows the dot.
+// An n (BigInt) or d (Decimal) suffix stays part of the literal
 func (l *Lexer) readNumber() string {
 	start := l.lastPosition
 	for isNumber(l.currentChar) {
 		l.readChar()
 	}
+
+	if l.currentChar == '.' && isNumber(l.peekChar()) {
+		l.readChar()
+		for isNumber(l.currentChar) {
+			l.readChar()
+		}
+	}
+
+	if (l.currentChar == 'n' || l.currentChar == 'd') && !isAlphanumeric(l.peekChar()) {
+		l.readChar()
+	}
+
 	return l.source[start:l.lastPosition]
 }
 
@@ -169,24 +315,33 @@ func (l *Lexer) skipWhitespace() {
 	}
 }
 
-func (l *Lexer) skipComments() {
-	if l.currentChar == '/' {
-		if l.peekChar() == '/' {
-			l.readChar()
-			for !l.isAtEnd() && l.currentChar != '\n' {
-				l.readChar()
-			}
-			l.readChar()
-		} else if l.peekChar() == '*' {
-			l.readChar()
-			for !l.isAtEnd() && !(l.currentChar == '*' && l.peekChar() == '/') {
-				l.readChar()
-			}
+// readComment consumes a comment and hands it back as trivia for the next token
+func (l *Lexer) readComment() token.Comment {
+	comment := token.Comment{Line: l.line, Column: l.column}
+	start := l.lastPosition
+
+	if l.peekChar() == '/' {
+		l.readChar()
+		for !l.isAtEnd() && l.currentChar != '\n' {
 			l.readChar()
+		}
+		comment.Text = l.source[start:l.lastPosition]
+		if l.currentChar != '\n' { // Comment on the last line, keep its final char ---
+			comment.Text = l.source[start:]
+		}
+		l.readChar()
+	} else {
+		l.readChar()
+		for !l.isAtEnd() && !(l.currentChar == '*' && l.peekChar() == '/') {
 			l.readChar()
 		}
+		l.readChar()
+		l.readChar()
+		comment.Text = l.source[start:min(l.lastPosition, len(l.source))]
 	}
+
 	l.skipWhitespace()
+	return comment
 }
 
 func (l *Lexer) readIdentifier() string {
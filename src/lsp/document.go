@@ -0,0 +1,153 @@
+package lsp
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/caelondev/monkey/src/analysis"
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/lexer"
+	"github.com/caelondev/monkey/src/parser"
+)
+
+type document struct {
+	uri     string
+	lines   []string
+	program *ast.Program
+	info    *analysis.Info
+
+	parseErrors []analysis.Diagnostic
+}
+
+// Parser errors are plain strings that start with their position ---
+var parseErrorPattern = regexp.MustCompile(`^\[Ln (\d+):(\d+)\]\s*(?:->\s*)?(.*)$`)
+
+func newDocument(uri, text string) *document {
+	p := parser.New(lexer.New(text))
+	program := p.ParseProgram()
+
+	doc := &document{
+		uri:     uri,
+		lines:   strings.Split(text, "\n"),
+		program: program,
+		info:    analysis.Analyze(program),
+	}
+
+	for _, msg := range p.Errors() {
+		d := analysis.Diagnostic{Line: 1, Column: 1, Message: msg}
+
+		if match := parseErrorPattern.FindStringSubmatch(msg); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			column, _ := strconv.Atoi(match[2])
+			d = analysis.Diagnostic{Line: uint(line), Column: uint(column), Message: match[3]}
+		}
+
+		doc.parseErrors = append(doc.parseErrors, d)
+	}
+
+	return doc
+}
+
+func (d *document) diagnostics() []diagnostic {
+	result := make([]diagnostic, 0)
+
+	for _, perr := range d.parseErrors {
+		result = append(result, diagnostic{
+			Range:    d.rangeOf(perr.Line, perr.Column, 1),
+			Severity: severityError,
+			Source:   "monkey",
+			Message:  perr.Message,
+		})
+	}
+
+	for _, diag := range analysis.Lint(d.program, d.info) {
+		severity := severityError
+		if diag.Severity == analysis.WARNING {
+			severity = severityWarning
+		}
+
+		result = append(result, diagnostic{
+			Range:    d.rangeOf(diag.Line, diag.Column, 1),
+			Severity: severity,
+			Source:   "monkey",
+			Message:  diag.Message,
+		})
+	}
+
+	return result
+}
+
+// identifierAt finds the identifier under the cursor, the cursor may sit
+// right after the last character as editors place it there when typing
+func (d *document) identifierAt(pos position) (*ast.Identifier, *analysis.Symbol) {
+	line := uint(pos.Line + 1)
+	column := d.byteColumn(pos)
+
+	for ident, sym := range d.info.Uses {
+		start := ident.Token.Column
+		end := start + uint(len(ident.Value))
+
+		if ident.Token.Line == line && start <= column && column <= end {
+			return ident, sym
+		}
+	}
+
+	return nil, nil
+}
+
+func (d *document) identRange(ident *ast.Identifier) textRange {
+	return d.rangeOf(ident.Token.Line, ident.Token.Column, len(ident.Value))
+}
+
+// rangeOf converts the lexer's 1-based byte positions into LSP's
+// 0-based UTF-16 positions
+func (d *document) rangeOf(line, column uint, length int) textRange {
+	start := d.position(line, column)
+	end := d.position(line, column+uint(length))
+	return textRange{Start: start, End: end}
+}
+
+func (d *document) position(line, column uint) position {
+	if line == 0 || int(line) > len(d.lines) {
+		return position{}
+	}
+
+	text := d.lines[line-1]
+	offset := min(int(column)-1, len(text))
+	if offset < 0 {
+		offset = 0
+	}
+
+	return position{Line: int(line) - 1, Character: utf16Length(text[:offset])}
+}
+
+func (d *document) byteColumn(pos position) uint {
+	if pos.Line < 0 || pos.Line >= len(d.lines) {
+		return 0
+	}
+
+	text := d.lines[pos.Line]
+	units := 0
+
+	for offset, r := range text {
+		if units >= pos.Character {
+			return uint(offset) + 1
+		}
+		units += len(utf16.Encode([]rune{r}))
+	}
+
+	return uint(len(text)) + 1
+}
+
+func utf16Length(s string) int {
+	units := 0
+	for len(s) > 0 {
+		r, size := utf8.DecodeRuneInString(s)
+		units += len(utf16.Encode([]rune{r}))
+		s = s[size:]
+	}
+	return units
+}
@@ -0,0 +1,171 @@
+package lsp
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/textproto"
+	"strconv"
+	"sync"
+)
+
+// NOTE: Only the slice of the Language Server Protocol that this server ---
+// actually answers is modelled here, see the specification for the rest ---
+// https://microsoft.github.io/language-server-protocol/specification ---
+
+type request struct {
+	JSONRPC string           `json:"jsonrpc"`
+	Id      *json.RawMessage `json:"id,omitempty"` // Absent on notifications ---
+	Method  string           `json:"method"`
+	Params  json.RawMessage  `json:"params,omitempty"`
+}
+
+type response struct {
+	JSONRPC string           `json:"jsonrpc"`
+	Id      *json.RawMessage `json:"id"`
+	Result  any              `json:"result"`
+}
+
+type errorResponse struct {
+	JSONRPC string           `json:"jsonrpc"`
+	Id      *json.RawMessage `json:"id"`
+	Error   rpcError         `json:"error"`
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+type notification struct {
+	JSONRPC string `json:"jsonrpc"`
+	Method  string `json:"method"`
+	Params  any    `json:"params"`
+}
+
+const (
+	methodNotFound = -32601
+	invalidParams  = -32602
+	requestFailed  = -32803
+)
+
+type position struct {
+	Line      int `json:"line"`
+	Character int `json:"character"`
+}
+
+type textRange struct {
+	Start position `json:"start"`
+	End   position `json:"end"`
+}
+
+type location struct {
+	URI   string    `json:"uri"`
+	Range textRange `json:"range"`
+}
+
+type textEdit struct {
+	Range   textRange `json:"range"`
+	NewText string    `json:"newText"`
+}
+
+type diagnostic struct {
+	Range    textRange `json:"range"`
+	Severity int       `json:"severity"`
+	Source   string    `json:"source"`
+	Message  string    `json:"message"`
+}
+
+type textDocumentPosition struct {
+	TextDocument struct {
+		URI string `json:"uri"`
+	} `json:"textDocument"`
+	Position position `json:"position"`
+}
+
+// LSP enum values ---
+const (
+	severityError   = 1
+	severityWarning = 2
+
+	completionMethod   = 2
+	completionFunction = 3
+	completionField    = 5
+	completionVariable = 6
+	completionEnum     = 13
+	completionKeyword  = 14
+	completionVariant  = 20
+	completionStruct   = 22
+
+	symbolField    = 8
+	symbolEnum     = 10
+	symbolFunction = 12
+	symbolVariable = 13
+	symbolStruct   = 23
+)
+
+// ---------------- Transport ----------------
+// Every message is a JSON body preceded by a Content-Length header
+
+type transport struct {
+	reader *bufio.Reader
+	writer io.Writer
+	mutex  sync.Mutex
+}
+
+func newTransport(in io.Reader, out io.Writer) *transport {
+	return &transport{reader: bufio.NewReader(in), writer: out}
+}
+
+func (t *transport) readRequest() (*request, error) {
+	headers, err := textproto.NewReader(t.reader).ReadMIMEHeader()
+	if err != nil {
+		return nil, err
+	}
+
+	length, err := strconv.Atoi(headers.Get("Content-Length"))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Content-Length header: %w", err)
+	}
+
+	body := make([]byte, length)
+	if _, err := io.ReadFull(t.reader, body); err != nil {
+		return nil, err
+	}
+
+	req := &request{}
+	if err := json.Unmarshal(body, req); err != nil {
+		return nil, err
+	}
+
+	return req, nil
+}
+
+func (t *transport) respond(req *request, result any) {
+	t.send(&response{JSONRPC: "2.0", Id: req.Id, Result: result})
+}
+
+func (t *transport) respondError(req *request, code int, format string, a ...any) {
+	t.send(&errorResponse{
+		JSONRPC: "2.0",
+		Id:      req.Id,
+		Error:   rpcError{Code: code, Message: fmt.Sprintf(format, a...)},
+	})
+}
+
+func (t *transport) notify(method string, params any) {
+	t.send(&notification{JSONRPC: "2.0", Method: method, Params: params})
+}
+
+func (t *transport) send(msg any) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	body, err := json.Marshal(msg)
+	if err != nil {
+		return
+	}
+
+	fmt.Fprintf(t.writer, "Content-Length: %d\r\n\r\n%s", len(body), body)
+}
@@ -0,0 +1,542 @@
+package lsp
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"slices"
+	"sort"
+	"strings"
+
+	"github.com/caelondev/monkey/src/analysis"
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/evaluation"
+	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/token"
+)
+
+var keywords = []string{"fn", "var", "const", "if", "else", "return", "assign", "xor", "struct", "enum", "match", "switch", "case", "default", "true", "false", "nil", "Inf", "NaN"}
+
+type server struct {
+	transport *transport
+	documents map[string]*document
+}
+
+// Serve answers Language Server Protocol requests over the given streams
+// until the client asks it to exit
+func Serve(in io.Reader, out io.Writer) error {
+	s := &server{
+		transport: newTransport(in, out),
+		documents: make(map[string]*document),
+	}
+
+	for {
+		req, err := s.transport.readRequest()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return err
+		}
+
+		if req.Method == "exit" {
+			return nil
+		}
+
+		s.handle(req)
+	}
+}
+
+func (s *server) handle(req *request) {
+	switch req.Method {
+	case "initialize":
+		s.transport.respond(req, map[string]any{
+			"capabilities": map[string]any{
+				"textDocumentSync":       1, // Full document on every change ---
+				"definitionProvider":     true,
+				"hoverProvider":          true,
+				"completionProvider":     map[string]any{},
+				"documentSymbolProvider": true,
+				"renameProvider":         true,
+			},
+			"serverInfo": map[string]any{"name": "monkey"},
+		})
+
+	case "shutdown":
+		s.transport.respond(req, nil)
+
+	case "textDocument/didOpen":
+		var params struct {
+			TextDocument struct {
+				URI  string `json:"uri"`
+				Text string `json:"text"`
+			} `json:"textDocument"`
+		}
+		json.Unmarshal(req.Params, &params)
+		s.update(params.TextDocument.URI, params.TextDocument.Text)
+
+	case "textDocument/didChange":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+			ContentChanges []struct {
+				Text string `json:"text"`
+			} `json:"contentChanges"`
+		}
+		json.Unmarshal(req.Params, &params)
+
+		if n := len(params.ContentChanges); n != 0 {
+			s.update(params.TextDocument.URI, params.ContentChanges[n-1].Text)
+		}
+
+	case "textDocument/didClose":
+		var params struct {
+			TextDocument struct {
+				URI string `json:"uri"`
+			} `json:"textDocument"`
+		}
+		json.Unmarshal(req.Params, &params)
+		delete(s.documents, params.TextDocument.URI)
+
+	case "textDocument/definition":
+		s.definition(req)
+	case "textDocument/hover":
+		s.hover(req)
+	case "textDocument/completion":
+		s.completion(req)
+	case "textDocument/documentSymbol":
+		s.documentSymbol(req)
+	case "textDocument/rename":
+		s.rename(req)
+
+	default:
+		// Unknown notifications are ignored, unknown requests need an answer ---
+		if req.Id != nil {
+			s.transport.respondError(req, methodNotFound, "Unsupported method '%s'", req.Method)
+		}
+	}
+}
+
+func (s *server) update(uri, text string) {
+	doc := newDocument(uri, text)
+	s.documents[uri] = doc
+
+	s.transport.notify("textDocument/publishDiagnostics", map[string]any{
+		"uri":         uri,
+		"diagnostics": doc.diagnostics(),
+	})
+}
+
+// lookup decodes a position request and finds the symbol under the cursor
+func (s *server) lookup(req *request) (*document, *ast.Identifier, *analysis.Symbol) {
+	var params textDocumentPosition
+	json.Unmarshal(req.Params, &params)
+
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		return nil, nil, nil
+	}
+
+	ident, sym := doc.identifierAt(params.Position)
+	return doc, ident, sym
+}
+
+func (s *server) definition(req *request) {
+	doc, _, sym := s.lookup(req)
+
+	if sym == nil || sym.Ident == nil {
+		s.transport.respond(req, nil)
+		return
+	}
+
+	s.transport.respond(req, location{URI: doc.uri, Range: doc.identRange(sym.Ident)})
+}
+
+func (s *server) hover(req *request) {
+	doc, ident, sym := s.lookup(req)
+
+	if sym == nil {
+		s.transport.respond(req, nil)
+		return
+	}
+
+	var contents strings.Builder
+	contents.WriteString("```monkey\n" + signature(sym) + "\n```\n")
+
+	switch {
+	case sym.Kind == analysis.SELF:
+		contents.WriteString("The instance the method was called on")
+	case sym.Ident == nil:
+		contents.WriteString("Native function")
+	default:
+		contents.WriteString(fmt.Sprintf("Declared at Ln %d:%d", sym.Ident.Token.Line, sym.Ident.Token.Column))
+	}
+
+	s.transport.respond(req, map[string]any{
+		"contents": map[string]any{"kind": "markdown", "value": contents.String()},
+		"range":    doc.identRange(ident),
+	})
+}
+
+func (s *server) completion(req *request) {
+	var params textDocumentPosition
+	json.Unmarshal(req.Params, &params)
+
+	items := make([]map[string]any, 0)
+	seen := make(map[string]bool)
+
+	add := func(label string, kind int, detail string) {
+		if seen[label] {
+			return
+		}
+		seen[label] = true
+		items = append(items, map[string]any{"label": label, "kind": kind, "detail": detail})
+	}
+
+	doc, ok := s.documents[params.TextDocument.URI]
+
+	// After a dot only members make sense, the value's type isn't known ---
+	// until it runs, so every built-in type offers its own ---
+	if ok && afterDot(doc, params.Position) {
+		for _, objectType := range []object.ObjectType{object.STRING_OBJECT, object.ARRAY_OBJECT, object.HASH_OBJECT} {
+			for _, name := range evaluation.MemberNames(objectType) {
+				add(name, completionMethod, strings.ToLower(string(objectType))+" member")
+			}
+		}
+
+		for _, sym := range doc.info.Symbols {
+			switch decl := sym.Decl.(type) {
+			case *ast.StructStatement:
+				if sym.Kind != analysis.STRUCT {
+					continue
+				}
+				for _, field := range decl.Fields {
+					add(field.Ident().Value, completionField, sym.Name+" field")
+				}
+				for _, method := range decl.Methods {
+					add(method.Name.Value, completionMethod, sym.Name+" method")
+				}
+			case *ast.EnumStatement:
+				for _, variant := range decl.Variants {
+					add(variant.Name.Value, completionVariant, sym.Name+" variant")
+				}
+			}
+		}
+
+		s.transport.respond(req, items)
+		return
+	}
+
+	for _, keyword := range keywords {
+		add(keyword, completionKeyword, "keyword")
+	}
+
+	for _, name := range evaluation.NativeFunctionNames() {
+		add(name, completionFunction, "native function")
+	}
+
+	if ok {
+		for _, sym := range doc.info.Symbols {
+			kind := completionVariable
+			switch sym.Kind {
+			case analysis.FUNCTION:
+				kind = completionFunction
+			case analysis.STRUCT:
+				kind = completionStruct
+			case analysis.ENUM:
+				kind = completionEnum
+			case analysis.FIELD:
+				// Fields are completed after a dot ---
+				continue
+			}
+			add(sym.Name, kind, signature(sym))
+		}
+	}
+
+	s.transport.respond(req, items)
+}
+
+func (s *server) documentSymbol(req *request) {
+	var params struct {
+		TextDocument struct {
+			URI string `json:"uri"`
+		} `json:"textDocument"`
+	}
+	json.Unmarshal(req.Params, &params)
+
+	symbols := make([]map[string]any, 0)
+
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		s.transport.respond(req, symbols)
+		return
+	}
+
+	for _, sym := range doc.info.Symbols {
+		if sym.Kind == analysis.PARAMETER {
+			continue
+		}
+
+		kind := symbolVariable
+		switch sym.Kind {
+		case analysis.FUNCTION:
+			kind = symbolFunction
+		case analysis.STRUCT:
+			kind = symbolStruct
+		case analysis.ENUM:
+			kind = symbolEnum
+		case analysis.FIELD:
+			kind = symbolField
+		}
+
+		entry := map[string]any{
+			"name":     sym.Name,
+			"kind":     kind,
+			"location": location{URI: doc.uri, Range: doc.identRange(sym.Ident)},
+		}
+
+		switch container := sym.Scope.Node.(type) {
+		case *ast.FunctionDeclarationStatement:
+			entry["containerName"] = container.Name.Value
+		case *ast.StructStatement:
+			entry["containerName"] = container.Name.Value
+		}
+
+		symbols = append(symbols, entry)
+	}
+
+	s.transport.respond(req, symbols)
+}
+
+func (s *server) rename(req *request) {
+	var params struct {
+		textDocumentPosition
+		NewName string `json:"newName"`
+	}
+	json.Unmarshal(req.Params, &params)
+
+	doc, ok := s.documents[params.TextDocument.URI]
+	if !ok {
+		s.transport.respond(req, nil)
+		return
+	}
+
+	_, sym := doc.identifierAt(params.Position)
+
+	switch {
+	case sym == nil:
+		s.transport.respondError(req, requestFailed, "There is no variable to rename here")
+		return
+	case sym.Kind == analysis.NATIVE:
+		s.transport.respondError(req, requestFailed, "Native function '%s' cannot be renamed", sym.Name)
+		return
+	case sym.Kind == analysis.SELF:
+		s.transport.respondError(req, requestFailed, "'self' cannot be renamed")
+		return
+	case sym.Kind == analysis.FIELD:
+		// Fields are read through values, p.x and self.x aren't references ---
+		s.transport.respondError(req, requestFailed, "Field '%s' cannot be renamed", sym.Name)
+		return
+	case !isIdentifier(params.NewName):
+		s.transport.respondError(req, invalidParams, "'%s' is not a valid identifier", params.NewName)
+		return
+	}
+
+	references := sym.References
+
+	// Calls can set a parameter by name, those names are renamed too ---
+	if sym.Kind == analysis.PARAMETER {
+		named, ok := doc.namedArguments(sym)
+		if !ok {
+			s.transport.respondError(req, requestFailed, "'%s' is passed by name to a call whose function isn't known, it cannot be renamed safely", sym.Name)
+			return
+		}
+		references = append(slices.Clone(references), named...)
+	}
+
+	// {name} reads key "name", renamed it has to keep the key: {name: n} ---
+	shorthand := doc.shorthandKeys()
+	edit := func(ident *ast.Identifier) textEdit {
+		if key, ok := shorthand[ident]; ok {
+			return textEdit{Range: doc.identRange(ident), NewText: key + ": " + params.NewName}
+		}
+		return textEdit{Range: doc.identRange(ident), NewText: params.NewName}
+	}
+
+	edits := []textEdit{edit(sym.Ident)}
+	for _, ref := range references {
+		edits = append(edits, edit(ref))
+	}
+
+	// Editors apply edits back to front, keep them deterministic ---
+	sort.Slice(edits, func(i, j int) bool {
+		a, b := edits[i].Range.Start, edits[j].Range.Start
+		return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+	})
+
+	s.transport.respond(req, map[string]any{
+		"changes": map[string][]textEdit{doc.uri: edits},
+	})
+}
+
+// namedArguments finds the name: value arguments that set the parameter sym.
+// ok is false when an argument of that name goes to a call whose function
+// can't be told from the source, it might be the parameter's
+func (d *document) namedArguments(sym *analysis.Symbol) (names []*ast.Identifier, ok bool) {
+	ok = true
+
+	ast.Inspect(d.program, func(node ast.Node) bool {
+		call, isCall := node.(*ast.CallExpression)
+		if !isCall {
+			return true
+		}
+
+		for _, arg := range call.Arguments {
+			named, isNamed := arg.(*ast.NamedArgument)
+			if !isNamed || named.Name != sym.Name {
+				continue
+			}
+
+			switch callee := d.callee(call); {
+			case callee == nil:
+				ok = false
+			case callee.Kind == analysis.FUNCTION && callee.Decl == sym.Decl:
+				names = append(names, &ast.Identifier{Token: named.Token, Value: named.Name})
+			}
+		}
+		return true
+	})
+
+	return names, ok
+}
+
+// shorthandKeys maps the names written alone in a hash or constructor
+// pattern, {name} or Rect(w: 1, h), to the key they also stand for
+func (d *document) shorthandKeys() map[*ast.Identifier]string {
+	keys := make(map[*ast.Identifier]string)
+
+	// A shorthand's name is parsed from the key's own token ---
+	add := func(key token.Token, value ast.Pattern) {
+		ident, ok := value.(*ast.Identifier)
+		if ok && ident.Token.Line == key.Line && ident.Token.Column == key.Column {
+			keys[ident] = key.Literal
+		}
+	}
+
+	ast.Inspect(d.program, func(node ast.Node) bool {
+		switch node := node.(type) {
+		case *ast.HashPattern:
+			for i, key := range node.Keys {
+				add(key.Token, node.Values[i])
+			}
+		case *ast.ConstructorPattern:
+			for i, key := range node.Keys {
+				add(key.Token, node.Values[i])
+			}
+		}
+		return true
+	})
+
+	return keys
+}
+
+// callee is the symbol a call is known to reach: a declared function, a
+// struct or native, or an enum for a variant. nil when it can't be told,
+// like for a function held in a variable or a method
+func (d *document) callee(call *ast.CallExpression) *analysis.Symbol {
+	target := call.Function
+	if member, ok := target.(*ast.MemberExpression); ok {
+		target = member.Target
+	}
+
+	ident, ok := target.(*ast.Identifier)
+	if !ok {
+		return nil
+	}
+
+	sym := d.info.Uses[ident]
+	if sym == nil {
+		return nil
+	}
+
+	switch sym.Kind {
+	case analysis.ENUM:
+		return sym
+	case analysis.FUNCTION, analysis.STRUCT, analysis.NATIVE:
+		if target == call.Function {
+			return sym
+		}
+	}
+
+	return nil
+}
+
+func signature(sym *analysis.Symbol) string {
+	switch sym.Kind {
+	case analysis.FUNCTION:
+		fn := sym.Decl.(*ast.FunctionDeclarationStatement)
+		params := make([]string, len(fn.Parameters))
+		for i, param := range fn.Parameters {
+			params[i] = param.String()
+		}
+		return fmt.Sprintf("fn %s(%s)", sym.Name, strings.Join(params, ", "))
+	case analysis.PARAMETER:
+		return "(parameter) " + sym.Name
+	case analysis.NATIVE:
+		return "fn " + sym.Name
+	case analysis.STRUCT:
+		structure := sym.Decl.(*ast.StructStatement)
+		if len(structure.Fields) == 0 {
+			return fmt.Sprintf("struct %s {}", sym.Name)
+		}
+
+		fields := make([]string, len(structure.Fields))
+		for i, field := range structure.Fields {
+			fields[i] = field.String()
+		}
+		return fmt.Sprintf("struct %s { %s }", sym.Name, strings.Join(fields, ", "))
+	case analysis.ENUM:
+		return sym.Decl.String()
+	case analysis.FIELD:
+		return "(field) " + sym.Name
+	case analysis.SELF:
+		return "self"
+	default:
+		return "var " + sym.Name
+	}
+}
+
+func isIdentifier(name string) bool {
+	if name == "" || token.LookupIdentifier(name) != token.IDENTIFIER {
+		return false
+	}
+
+	for i, ch := range name {
+		letter := ch == '_' || ('a' <= ch && ch <= 'z') || ('A' <= ch && ch <= 'Z')
+		if !letter && (i == 0 || ch < '0' || ch > '9') {
+			return false
+		}
+	}
+
+	return true
+}
+
+// afterDot tells whether the name being typed at pos follows a dot
+func afterDot(doc *document, pos position) bool {
+	if pos.Line >= len(doc.lines) {
+		return false
+	}
+
+	line := doc.lines[pos.Line]
+	i := min(pos.Character, len(line))
+	for i > 0 && isNameByte(line[i-1]) {
+		i--
+	}
+
+	return i > 0 && line[i-1] == '.'
+}
+
+func isNameByte(c byte) bool {
+	return c == '_' || ('a' <= c && c <= 'z') || ('A' <= c && c <= 'Z') || ('0' <= c && c <= '9')
+}
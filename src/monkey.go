@@ -4,8 +4,15 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/caelondev/monkey/src/analysis"
+	"github.com/caelondev/monkey/src/debugger"
+	"github.com/caelondev/monkey/src/format"
+	"github.com/caelondev/monkey/src/lexer"
+	"github.com/caelondev/monkey/src/lsp"
+	"github.com/caelondev/monkey/src/parser"
 	"github.com/caelondev/monkey/src/repl"
 	"github.com/caelondev/monkey/src/run"
+	"github.com/jwalton/gchalk"
 )
 
 func Main() {
@@ -13,10 +20,152 @@ func Main() {
 
 	if len(args) == 1 {
 		repl.Start(os.Stdin, os.Stdout)
-	} else if len(args) == 2 {
-		run.RunFile(args[1])
-	} else {
-		fmt.Printf("Usage: monkey [filepath]")
-		os.Exit(0)
+		return
 	}
+
+	switch args[1] {
+	case "debug":
+		runDebug(args[2:])
+	case "lsp":
+		runLsp()
+	case "fmt":
+		runFormat(args[2:])
+	case "lint":
+		runLint(args[2:])
+	case "--opt":
+		runOptimized(args[2:])
+	default:
+		if len(args) != 2 {
+			printUsage()
+		}
+		run.RunFile(args[1], run.Options{})
+	}
+}
+
+func runOptimized(args []string) {
+	dump := len(args) != 0 && args[0] == "--dump-ast"
+	if dump {
+		args = args[1:]
+	}
+
+	if len(args) != 1 {
+		printUsage()
+	}
+
+	run.RunFile(args[0], run.Options{Optimize: true, DumpAST: dump})
+}
+
+func runDebug(args []string) {
+	if len(args) != 1 || args[0] != "--dap" {
+		printUsage()
+	}
+
+	if err := debugger.Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Debug adapter stopped: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func runLsp() {
+	if err := lsp.Serve(os.Stdin, os.Stdout); err != nil {
+		fmt.Fprintf(os.Stderr, "Language server stopped: %s\n", err.Error())
+		os.Exit(1)
+	}
+}
+
+func runFormat(args []string) {
+	write := len(args) != 0 && args[0] == "-w"
+	if write {
+		args = args[1:]
+	}
+
+	if len(args) == 0 {
+		printUsage()
+	}
+
+	failed := false
+
+	for _, path := range args {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "An error occurred whilst trying to read file:\n%s\n", err.Error())
+			failed = true
+			continue
+		}
+
+		formatted, err := format.Source(string(source))
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Cannot format '%s':\n%s\n", path, err.Error())
+			failed = true
+			continue
+		}
+
+		if !write {
+			fmt.Print(formatted)
+			continue
+		}
+
+		if formatted != string(source) {
+			if err := os.WriteFile(path, []byte(formatted), 0o644); err != nil {
+				fmt.Fprintf(os.Stderr, "Cannot write '%s': %s\n", path, err.Error())
+				failed = true
+			}
+		}
+	}
+
+	if failed {
+		os.Exit(1)
+	}
+}
+
+func runLint(args []string) {
+	if len(args) == 0 {
+		printUsage()
+	}
+
+	found := false
+
+	for _, path := range args {
+		source, err := os.ReadFile(path)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "An error occurred whilst trying to read file:\n%s\n", err.Error())
+			found = true
+			continue
+		}
+
+		p := parser.New(lexer.New(string(source)))
+		program := p.ParseProgram()
+
+		if len(p.Errors()) != 0 {
+			for _, msg := range p.Errors() {
+				fmt.Printf("%s: %s %s\n", path, gchalk.Red("error"), msg)
+			}
+			found = true
+			continue
+		}
+
+		for _, d := range analysis.Lint(program, analysis.Analyze(program)) {
+			severity := gchalk.Red("error")
+			if d.Severity == analysis.WARNING {
+				severity = gchalk.Yellow("warning")
+			}
+
+			fmt.Printf("%s:%d:%d: %s %s\n", path, d.Line, d.Column, severity, d.Message)
+			found = true
+		}
+	}
+
+	if found {
+		os.Exit(1)
+	}
+}
+
+func printUsage() {
+	fmt.Printf("Usage: monkey [filepath]\n")
+	fmt.Printf("       monkey --opt [--dump-ast] <filepath>\n")
+	fmt.Printf("       monkey debug --dap\n")
+	fmt.Printf("       monkey lsp\n")
+	fmt.Printf("       monkey fmt [-w] <filepath>...\n")
+	fmt.Printf("       monkey lint <filepath>...\n")
+	os.Exit(0)
 }
@@ -1,48 +1,154 @@
 package object
 
+import "sort"
+
+// NOTE: Variables the resolver could place live in slots, indexed by ---
+// the (depth, slot) pair it wrote on the identifier. Everything else, ---
+// like globals and code that never went through the resolver, falls ---
+// back to a by-name lookup ---
+
 type Environment struct {
-	store map[string]Object
-	outer *Environment
+	slots  []Object
+	layout []string       // Name of every slot, shared by all calls of a function ---
+	names  map[string]int // Slots of names declared at runtime ---
+	outer  *Environment
+
+	constants map[int]bool // Slots declared with const, nil until there is one ---
 }
 
 func NewEnvironment(outer *Environment) *Environment {
-	s := make(map[string]Object)
 	return &Environment{
-		store: s,
+		names: make(map[string]int),
 		outer: outer,
 	}
 }
 
-func (e *Environment) Get(name string) (Object, bool) {
-	if e == nil {
-		return nil, false
+// NewFunctionEnvironment makes the environment of a function call, with
+// room for every slot the resolver laid out for its body
+func NewFunctionEnvironment(outer *Environment, layout []string) *Environment {
+	return &Environment{
+		slots:  make([]Object, len(layout)),
+		layout: layout,
+		outer:  outer,
 	}
+}
 
-	if obj, ok := e.store[name]; ok {
-		return obj, true
+func (e *Environment) Get(name string) (Object, bool) {
+	for env := e; env != nil; env = env.outer {
+		if slot, ok := env.slotOf(name); ok {
+			return env.slots[slot], true
+		}
 	}
 
-	if e.outer != nil {
-		return e.outer.Get(name)
+	return nil, false
+}
+
+// GetAt reads a resolved variable. A slot that is still empty belongs to a
+// variable whose declaration hasn't run yet
+func (e *Environment) GetAt(depth, slot int) (Object, bool) {
+	env := e.ancestor(depth)
+	if env == nil || slot >= len(env.slots) || env.slots[slot] == nil {
+		return nil, false
 	}
 
-	return nil, false
+	return env.slots[slot], true
 }
 
 func (e *Environment) Set(name string, value Object) (Object, bool) {
 	exists := e.DoesExist(name)
 
-	e.store[name] = value
+	e.Declare(name, value)
 	return value, exists
 }
 
 func (e *Environment) Declare(name string, value Object) Object {
-	e.store[name] = value
+	if slot, ok := e.layoutSlot(name); ok {
+		e.slots[slot] = value
+		delete(e.constants, slot)
+		return value
+	}
+
+	if slot, ok := e.names[name]; ok {
+		e.slots[slot] = value
+		delete(e.constants, slot)
+		return value
+	}
+
+	if e.names == nil {
+		e.names = make(map[string]int)
+	}
+
+	e.names[name] = len(e.slots)
+	e.slots = append(e.slots, value)
+	return value
+}
+
+func (e *Environment) DeclareAt(slot int, value Object) Object {
+	for slot >= len(e.slots) {
+		e.slots = append(e.slots, nil)
+	}
+
+	e.slots[slot] = value
+	delete(e.constants, slot)
+	return value
+}
+
+// DeclareConstant is Declare for a binding that can't be reassigned
+func (e *Environment) DeclareConstant(name string, value Object) Object {
+	e.Declare(name, value)
+
+	slot, _ := e.slotOf(name)
+	e.markConstant(slot)
+	return value
+}
+
+func (e *Environment) DeclareConstantAt(slot int, value Object) Object {
+	e.DeclareAt(slot, value)
+	e.markConstant(slot)
 	return value
 }
 
+// IsConstant tells whether the variable name resolves to was declared with
+// const
+func (e *Environment) IsConstant(name string) bool {
+	for env := e; env != nil; env = env.outer {
+		if slot, ok := env.slotOf(name); ok {
+			return env.constants[slot]
+		}
+	}
+
+	return false
+}
+
+func (e *Environment) IsConstantAt(depth, slot int) bool {
+	env := e.ancestor(depth)
+	return env != nil && env.constants[slot]
+}
+
+// Assign updates an existing variable in whichever scope declared it
+func (e *Environment) Assign(name string, value Object) bool {
+	for env := e; env != nil; env = env.outer {
+		if slot, ok := env.slotOf(name); ok {
+			env.slots[slot] = value
+			return true
+		}
+	}
+
+	return false
+}
+
+func (e *Environment) AssignAt(depth, slot int, value Object) bool {
+	env := e.ancestor(depth)
+	if env == nil || slot >= len(env.slots) || env.slots[slot] == nil {
+		return false
+	}
+
+	env.slots[slot] = value
+	return true
+}
+
 func (e *Environment) DoesExist(name string) bool {
-	_, result := e.store[name]
+	_, result := e.slotOf(name)
 
 	return result
 }
@@ -50,3 +156,55 @@ func (e *Environment) DoesExist(name string) bool {
 func (e *Environment) GetOuter() *Environment {
 	return e.outer
 }
+
+// Names returns the names declared directly in this scope, sorted
+func (e *Environment) Names() []string {
+	names := make([]string, 0, len(e.slots))
+	for slot, name := range e.layout {
+		if e.slots[slot] != nil {
+			names = append(names, name)
+		}
+	}
+
+	for name := range e.names {
+		names = append(names, name)
+	}
+
+	sort.Strings(names)
+	return names
+}
+
+func (e *Environment) markConstant(slot int) {
+	if e.constants == nil {
+		e.constants = make(map[int]bool)
+	}
+	e.constants[slot] = true
+}
+
+func (e *Environment) ancestor(depth int) *Environment {
+	env := e
+	for ; depth > 0 && env != nil; depth-- {
+		env = env.outer
+	}
+
+	return env
+}
+
+func (e *Environment) slotOf(name string) (int, bool) {
+	if slot, ok := e.layoutSlot(name); ok && e.slots[slot] != nil {
+		return slot, true
+	}
+
+	slot, ok := e.names[name]
+	return slot, ok
+}
+
+func (e *Environment) layoutSlot(name string) (int, bool) {
+	for slot, layoutName := range e.layout {
+		if layoutName == name {
+			return slot, true
+		}
+	}
+
+	return 0, false
+}
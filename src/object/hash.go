@@ -0,0 +1,131 @@
+package object
+
+import (
+	"bytes"
+	"strconv"
+	"strings"
+)
+
+// HashKey is what a hash stores its pairs under. Every number hashes by its
+// exact decimal value, so 1, 1.0 and 1.00d are the same key, just as
+// they are == to each other
+type HashKey struct {
+	Type  ObjectType
+	Value string
+}
+
+type Hashable interface {
+	Object
+	HashKey() HashKey
+}
+
+func numberKey(text string) HashKey {
+	if text == "-0" {
+		text = "0"
+	}
+	return HashKey{Type: NUMBER_OBJECT, Value: text}
+}
+
+func (o *String) HashKey() HashKey {
+	return HashKey{Type: STRING_OBJECT, Value: o.Value}
+}
+
+func (o *Boolean) HashKey() HashKey {
+	return HashKey{Type: BOOLEAN_OBJECT, Value: strconv.FormatBool(o.Value)}
+}
+
+func (o *Integer) HashKey() HashKey {
+	return numberKey(strconv.FormatInt(o.Value, 10))
+}
+
+func (o *BigInt) HashKey() HashKey {
+	return numberKey(o.Value.String())
+}
+
+func (o *Number) HashKey() HashKey {
+	return numberKey(strconv.FormatFloat(o.Value, 'f', -1, 64))
+}
+
+func (o *Decimal) HashKey() HashKey {
+	text := o.Inspect()
+	if strings.Contains(text, ".") {
+		text = strings.TrimRight(strings.TrimRight(text, "0"), ".")
+	}
+	return numberKey(text)
+}
+
+type HashPair struct {
+	Key   Object
+	Value Object
+}
+
+type Hash struct {
+	Pairs  map[HashKey]*HashPair
+	Order  []HashKey // Insertion order, Inspect follows it ---
+	Frozen bool      // Set by freeze, no pair can be added or changed ---
+}
+
+func NewHash() *Hash {
+	return &Hash{Pairs: make(map[HashKey]*HashPair)}
+}
+
+func (o *Hash) Type() ObjectType {
+	return HASH_OBJECT
+}
+
+func (o *Hash) Get(key Hashable) (Object, bool) {
+	pair, ok := o.Pairs[key.HashKey()]
+	if !ok {
+		return nil, false
+	}
+	return pair.Value, true
+}
+
+// Set adds or replaces a pair, a replaced key keeps its place
+func (o *Hash) Set(key Hashable, value Object) {
+	hashKey := key.HashKey()
+
+	if pair, ok := o.Pairs[hashKey]; ok {
+		pair.Value = value
+		return
+	}
+
+	o.Pairs[hashKey] = &HashPair{Key: key, Value: value}
+	o.Order = append(o.Order, hashKey)
+}
+
+// Each visits the pairs in insertion order
+func (o *Hash) Each(visit func(pair *HashPair)) {
+	for _, key := range o.Order {
+		visit(o.Pairs[key])
+	}
+}
+
+func (o *Hash) Inspect() string {
+	return o.inspect(visited{})
+}
+
+func (o *Hash) inspect(seen visited) string {
+	if seen.enter(o) {
+		return "{...}"
+	}
+	defer seen.leave(o)
+
+	var out bytes.Buffer
+
+	out.WriteString("{")
+
+	for i, key := range o.Order {
+		pair := o.Pairs[key]
+		out.WriteString(seen.inspect(pair.Key))
+		out.WriteString(": ")
+		out.WriteString(seen.inspect(pair.Value))
+		if i != len(o.Order)-1 {
+			out.WriteString(", ")
+		}
+	}
+
+	out.WriteString("}")
+
+	return out.String()
+}
@@ -3,6 +3,8 @@ package object
 import (
 	"bytes"
 	"fmt"
+	"math/big"
+	"strconv"
 
 	"github.com/caelondev/monkey/src/ast"
 )
@@ -11,15 +13,24 @@ type ObjectType string
 
 const (
 	NUMBER_OBJECT       = "NUMBER"
+	INTEGER_OBJECT      = "INTEGER"
+	BIGINT_OBJECT       = "BIGINT"
+	DECIMAL_OBJECT      = "DECIMAL"
 	STRING_OBJECT       = "STRING"
 	ARRAY_OBJECT        = "ARRAY"
+	HASH_OBJECT         = "HASH"
 	BOOLEAN_OBJECT      = "BOOLEAN"
 	NIL_OBJECT          = "NIL"
 	NAN_OBJECT          = "NAN"
 	INFINITY_OBJECT     = "INFINITY"
 	RETURN_VALUE_OBJECT = "RETURN_VALUE"
+	TAIL_CALL_OBJECT    = "TAIL_CALL"
 	ERROR_OBJECT        = "ERROR"
 	FUNCTION_OBJECT     = "FUNCTION"
+	STRUCT_OBJECT       = "STRUCT"
+	INSTANCE_OBJECT     = "INSTANCE"
+	ENUM_OBJECT         = "ENUM"
+	VARIANT_OBJECT      = "VARIANT"
 )
 
 var (
@@ -60,6 +71,62 @@ func (o *Number) Inspect() string {
 	return fmt.Sprintf("%g", o.Value)
 }
 
+type Integer struct {
+	Value int64
+}
+
+func (o *Integer) Type() ObjectType {
+	return INTEGER_OBJECT
+}
+
+func (o *Integer) Inspect() string {
+	return strconv.FormatInt(o.Value, 10)
+}
+
+// BigInt is where integers go once they overflow an int64, they don't come
+// back down when the value shrinks again
+type BigInt struct {
+	Value *big.Int
+}
+
+func (o *BigInt) Type() ObjectType {
+	return BIGINT_OBJECT
+}
+
+func (o *BigInt) Inspect() string {
+	return o.Value.String()
+}
+
+// Decimal is Unscaled * 10^-Scale, exact where floats round. The scale is
+// kept as written, 1.10d prints as 1.10
+type Decimal struct {
+	Unscaled *big.Int
+	Scale    int
+}
+
+func (o *Decimal) Type() ObjectType {
+	return DECIMAL_OBJECT
+}
+
+func (o *Decimal) Inspect() string {
+	digits := new(big.Int).Abs(o.Unscaled).String()
+	sign := ""
+	if o.Unscaled.Sign() < 0 {
+		sign = "-"
+	}
+
+	if o.Scale <= 0 {
+		return sign + digits
+	}
+
+	for len(digits) <= o.Scale {
+		digits = "0" + digits
+	}
+
+	point := len(digits) - o.Scale
+	return sign + digits[:point] + "." + digits[point:]
+}
+
 type Boolean struct {
 	Value bool
 }
@@ -120,6 +187,29 @@ func (o *ReturnValue) Inspect() string {
 	return fmt.Sprintf("return { %s }", o.Value.Inspect())
 }
 
+// TailCall is a call in tail position that hasn't happened yet. It never
+// escapes the function it was made in, the caller runs it in place
+type TailCall struct {
+	Function  *Function
+	Arguments []Object
+	Named     []NamedArgument
+	CallNode  *ast.CallExpression
+}
+
+// NamedArgument is an evaluated name: value argument
+type NamedArgument struct {
+	Name  string
+	Value Object
+}
+
+func (o *TailCall) Type() ObjectType {
+	return TAIL_CALL_OBJECT
+}
+
+func (o *TailCall) Inspect() string {
+	return fmt.Sprintf("tail call { %s }", o.Function.Inspect())
+}
+
 type Error struct {
 	Line    uint
 	Column  uint
@@ -137,9 +227,10 @@ func (o *Error) Inspect() string {
 }
 
 type Function struct {
-	Parameters []*ast.Identifier
+	Parameters []*ast.Parameter
 	Name       *ast.Identifier
 	Body       *ast.BlockStatement
+	Slots      []string
 	Scope      *Environment
 }
 
@@ -161,7 +252,8 @@ type NativeFunctionFn func(
 ) Object
 
 type NativeFunction struct {
-	Fn NativeFunctionFn
+	Fn         NativeFunctionFn
+	Parameters []string // Names its arguments can be passed by, nil for none ---
 }
 
 func (o *NativeFunction) Type() ObjectType {
@@ -174,6 +266,7 @@ func (o *NativeFunction) Inspect() string {
 
 type Array struct {
 	Elements []Object
+	Frozen   bool // Set by freeze, its elements can't change anymore ---
 }
 
 func (o *Array) Type() ObjectType {
@@ -181,12 +274,21 @@ func (o *Array) Type() ObjectType {
 }
 
 func (o *Array) Inspect() string {
+	return o.inspect(visited{})
+}
+
+func (o *Array) inspect(seen visited) string {
+	if seen.enter(o) {
+		return "[...]"
+	}
+	defer seen.leave(o)
+
 	var out bytes.Buffer
 
 	out.WriteString("[")
 
 	for i, elem := range o.Elements {
-		out.WriteString(elem.Inspect())
+		out.WriteString(seen.inspect(elem))
 		if i != len(o.Elements)-1 {
 			out.WriteString(", ")
 		}
@@ -196,3 +298,163 @@ func (o *Array) Inspect() string {
 
 	return out.String()
 }
+
+// visited holds the collections being printed, so one that holds itself
+// prints a placeholder where it comes round again instead of recursing
+type visited map[Object]bool
+
+// enter marks a collection as being printed, true when it already was
+func (v visited) enter(obj Object) bool {
+	if v[obj] {
+		return true
+	}
+	v[obj] = true
+	return false
+}
+
+// leave unmarks it, a value held twice without a cycle still prints in full
+func (v visited) leave(obj Object) {
+	delete(v, obj)
+}
+
+func (v visited) inspect(obj Object) string {
+	if nested, ok := obj.(interface{ inspect(visited) string }); ok {
+		return nested.inspect(v)
+	}
+	return obj.Inspect()
+}
+
+// Struct is a declared struct, calling it builds an Instance
+type Struct struct {
+	Name    string
+	Fields  []*ast.Parameter
+	Methods map[string]*ast.FunctionDeclarationStatement
+	Scope   *Environment
+}
+
+func (o *Struct) Type() ObjectType {
+	return STRUCT_OBJECT
+}
+
+func (o *Struct) Inspect() string {
+	return fmt.Sprintf("[ Struct '%s' ]", o.Name)
+}
+
+// FieldIndex finds where an instance keeps the field name, -1 when the
+// struct has no such field
+func (o *Struct) FieldIndex(name string) int {
+	for i, field := range o.Fields {
+		if field.Ident().Value == name {
+			return i
+		}
+	}
+
+	return -1
+}
+
+type Instance struct {
+	Struct *Struct
+	Values []Object // In the order of Struct.Fields ---
+	Frozen bool     // Set by freeze, its fields can't change anymore ---
+}
+
+func (o *Instance) Type() ObjectType {
+	return INSTANCE_OBJECT
+}
+
+func (o *Instance) Inspect() string {
+	return o.inspect(visited{})
+}
+
+func (o *Instance) inspect(seen visited) string {
+	if seen.enter(o) {
+		return o.Struct.Name + "(...)"
+	}
+	defer seen.leave(o)
+
+	var out bytes.Buffer
+
+	out.WriteString(o.Struct.Name)
+	out.WriteString("(")
+
+	for i, value := range o.Values {
+		if i != 0 {
+			out.WriteString(", ")
+		}
+		out.WriteString(o.Struct.Fields[i].Ident().Value)
+		out.WriteString(": ")
+		out.WriteString(seen.inspect(value))
+	}
+
+	out.WriteString(")")
+
+	return out.String()
+}
+
+// Enum is a declared enum, its variants are read off it with a dot
+type Enum struct {
+	Name     string
+	Variants []*ast.EnumVariant
+	Units    map[string]*Variant // The one value of each variant without fields ---
+}
+
+func (o *Enum) Type() ObjectType {
+	return ENUM_OBJECT
+}
+
+func (o *Enum) Inspect() string {
+	return fmt.Sprintf("[ Enum '%s' ]", o.Name)
+}
+
+// Variant finds the declaration of the variant name, nil when there's none
+func (o *Enum) Variant(name string) *ast.EnumVariant {
+	for _, variant := range o.Variants {
+		if variant.Name.Value == name {
+			return variant
+		}
+	}
+
+	return nil
+}
+
+type Variant struct {
+	Enum   *Enum
+	Decl   *ast.EnumVariant
+	Values []Object // In the order of Decl.Fields ---
+}
+
+func (o *Variant) Type() ObjectType {
+	return VARIANT_OBJECT
+}
+
+func (o *Variant) Inspect() string {
+	return o.inspect(visited{})
+}
+
+// A variant can't hold itself, it only passes seen on to its fields ---
+func (o *Variant) inspect(seen visited) string {
+	var out bytes.Buffer
+
+	out.WriteString(o.Enum.Name)
+	out.WriteString(".")
+	out.WriteString(o.Decl.Name.Value)
+
+	if len(o.Values) == 0 {
+		return out.String()
+	}
+
+	out.WriteString("(")
+
+	for i, value := range o.Values {
+		if i != 0 {
+			out.WriteString(", ")
+		}
+		out.WriteString(o.Decl.Fields[i].Value)
+		out.WriteString(": ")
+		out.WriteString(seen.inspect(value))
+	}
+
+	out.WriteString(")")
+
+	return out.String()
+}
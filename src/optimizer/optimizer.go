@@ -0,0 +1,396 @@
+package optimizer
+
+import (
+	"math"
+	"math/big"
+	"strconv"
+	"strings"
+
+	"github.com/caelondev/monkey/src/ast"
+	"github.com/caelondev/monkey/src/evaluation"
+	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/token"
+)
+
+// NOTE: Constant subtrees are folded by handing them to the evaluator ---
+// itself, so the Inf/NaN rules can't drift from what a run would do. ---
+// Anything that would be a runtime error is left alone to fail at ---
+// runtime, with its position ---
+
+type optimizer struct {
+	evaluator evaluation.Evaluator
+	env       *object.Environment
+}
+
+// Optimize rewrites the program in place and returns it. It runs after the
+// resolver, folded and dropped nodes never hold identifiers so every
+// binding stays valid
+func Optimize(program *ast.Program) *ast.Program {
+	o := &optimizer{evaluator: evaluation.New(), env: object.NewEnvironment(nil)}
+	program.Statements = o.statements(program.Statements)
+
+	return program
+}
+
+/*
+* [ STATEMENTS ]
+**/
+
+func (o *optimizer) statements(statements []ast.Statement) []ast.Statement {
+	result := make([]ast.Statement, 0, len(statements))
+
+	for i, stmt := range statements {
+		// The last statement is the block's value, functions return it ---
+		result = append(result, o.statement(stmt, i == len(statements)-1)...)
+	}
+
+	return result
+}
+
+func (o *optimizer) statement(node ast.Statement, last bool) []ast.Statement {
+	switch node := node.(type) {
+	case *ast.VarStatement:
+		node.Value = o.expression(node.Value)
+	case *ast.BatchAssignmentStatement:
+		node.NewValue = o.expression(node.NewValue)
+	case *ast.ReturnStatement:
+		if node.ReturnValue != nil {
+			node.ReturnValue = o.expression(node.ReturnValue)
+		}
+	case *ast.ExpressionStatement:
+		node.Expression = o.expression(node.Expression)
+		if !last && isPure(node.Expression) {
+			return nil
+		}
+	case *ast.FunctionDeclarationStatement:
+		o.parameters(node.Parameters)
+		o.block(node.Body)
+	case *ast.StructStatement:
+		o.parameters(node.Fields)
+		for _, method := range node.Methods {
+			o.parameters(method.Parameters)
+			o.block(method.Body)
+		}
+	case *ast.BlockStatement:
+		o.block(node)
+	case *ast.IfStatement:
+		return o.ifStatement(node, last)
+	case *ast.SwitchStatement:
+		node.Subject = o.expression(node.Subject)
+		for _, c := range node.Cases {
+			for i, value := range c.Values {
+				c.Values[i] = o.expression(value)
+			}
+			o.block(c.Body)
+		}
+	}
+
+	return []ast.Statement{node}
+}
+
+func (o *optimizer) ifStatement(node *ast.IfStatement, last bool) []ast.Statement {
+	node.Condition = o.expression(node.Condition)
+	node.Consequence = o.branch(node.Consequence)
+	if node.Alternative != nil {
+		node.Alternative = o.branch(node.Alternative)
+	}
+
+	condition, ok := o.constant(node.Condition)
+	if !ok {
+		return []ast.Statement{node}
+	}
+
+	taken := node.Alternative
+	if evaluation.IsTruthy(condition) {
+		taken = node.Consequence
+	}
+
+	switch {
+	case taken == nil && last:
+		// A skipped if is nil, which may be what the function returns ---
+		return []ast.Statement{node}
+	case taken == nil:
+		return nil
+	case last:
+		return []ast.Statement{taken}
+	}
+
+	// if-blocks share their scope, so the branch can be spliced in ---
+	if block, ok := taken.(*ast.BlockStatement); ok {
+		return block.Statements
+	}
+
+	return []ast.Statement{taken}
+}
+
+// branch optimizes an if body, a lone statement that turns into several
+// gets wrapped in a block
+func (o *optimizer) branch(node ast.Statement) ast.Statement {
+	if block, ok := node.(*ast.BlockStatement); ok {
+		o.block(block)
+		return block
+	}
+
+	statements := o.statement(node, true)
+	if len(statements) == 1 {
+		return statements[0]
+	}
+
+	return &ast.BlockStatement{Token: token.Token{Type: token.LEFT_BRACE, Literal: "{", Line: node.GetLine(), Column: node.GetColumn()}, Statements: statements}
+}
+
+func (o *optimizer) block(block *ast.BlockStatement) {
+	if block != nil {
+		block.Statements = o.statements(block.Statements)
+	}
+}
+
+/*
+* [ EXPRESSIONS ]
+**/
+
+func (o *optimizer) expression(node ast.Expression) ast.Expression {
+	switch node := node.(type) {
+	case *ast.UnaryExpression:
+		node.Right = o.expression(node.Right)
+		return o.fold(node)
+
+	case *ast.BinaryExpression:
+		node.Left = o.expression(node.Left)
+		node.Right = o.expression(node.Right)
+		return o.fold(node)
+
+	case *ast.TernaryExpression:
+		node.Condition = o.expression(node.Condition)
+		node.Consequence = o.expression(node.Consequence)
+		node.Alternative = o.expression(node.Alternative)
+
+		if condition, ok := o.constant(node.Condition); ok {
+			if evaluation.IsTruthy(condition) {
+				return node.Consequence
+			}
+			return node.Alternative
+		}
+
+	case *ast.CallExpression:
+		node.Function = o.expression(node.Function)
+		for i, arg := range node.Arguments {
+			node.Arguments[i] = o.expression(arg)
+		}
+
+	case *ast.PipeExpression:
+		// Calls are never folded, so it stays the same node ---
+		o.expression(node.Call)
+
+	case *ast.AssignmentExpression:
+		node.Assignee = o.expression(node.Assignee)
+		node.NewValue = o.expression(node.NewValue)
+
+	case *ast.UpdateExpression:
+		node.Target = o.expression(node.Target)
+
+	case *ast.HashLiteral:
+		for i, key := range node.Keys {
+			node.Keys[i] = o.expression(key)
+			node.Values[i] = o.expression(node.Values[i])
+		}
+
+	case *ast.ArrayLiteral:
+		for i, elem := range node.Elements {
+			node.Elements[i] = o.expression(elem)
+		}
+
+	case *ast.IndexExpression:
+		node.Target = o.expression(node.Target)
+		node.Index = o.expression(node.Index)
+
+	case *ast.MemberExpression:
+		node.Target = o.expression(node.Target)
+
+	case *ast.MatchExpression:
+		node.Subject = o.expression(node.Subject)
+		for _, arm := range node.Arms {
+			arm.Guard = o.expression(arm.Guard)
+			arm.Body = o.expression(arm.Body)
+		}
+
+	case *ast.SliceExpression:
+		node.Target = o.expression(node.Target)
+		node.Start = o.expression(node.Start)
+		node.End = o.expression(node.End)
+		node.Step = o.expression(node.Step)
+
+	case *ast.SpreadExpression:
+		node.Value = o.expression(node.Value)
+
+	case *ast.NamedArgument:
+		node.Value = o.expression(node.Value)
+
+	case *ast.FunctionLiteral:
+		o.parameters(node.Parameters)
+		o.block(node.Body)
+	}
+
+	return node
+}
+
+func (o *optimizer) parameters(params []*ast.Parameter) {
+	for _, param := range params {
+		if param.Default != nil {
+			param.Default = o.expression(param.Default)
+		}
+	}
+}
+
+// fold replaces a constant subtree with the literal it evaluates to
+func (o *optimizer) fold(node ast.Expression) ast.Expression {
+	if isLiteral(node) || !isConstant(node) {
+		return node
+	}
+
+	value, ok := o.constant(node)
+	if !ok {
+		return node
+	}
+
+	if folded := literalOf(value, node); folded != nil {
+		return folded
+	}
+
+	return node
+}
+
+func (o *optimizer) constant(node ast.Expression) (object.Object, bool) {
+	if !isConstant(node) {
+		return nil, false
+	}
+
+	value := o.evaluator.Evaluate(node, o.env)
+	if value == nil || value.Type() == object.ERROR_OBJECT {
+		return nil, false
+	}
+
+	return value, true
+}
+
+/*
+* [ HELPERS ]
+**/
+
+// literalOf builds the literal for a folded value at the position of the
+// node it replaces. Negative numbers are spelled as a negation, which is
+// what the parser would have made of them
+func literalOf(value object.Object, at ast.Node) ast.Expression {
+	tok := func(tokenType token.TokenType, literal string) token.Token {
+		return token.Token{Type: tokenType, Literal: literal, Line: at.GetLine(), Column: at.GetColumn()}
+	}
+
+	negate := func(right ast.Expression) ast.Expression {
+		return &ast.UnaryExpression{Token: tok(token.MINUS, "-"), Operator: tok(token.MINUS, "-"), Right: right}
+	}
+
+	switch value := value.(type) {
+	case *object.Number:
+		abs := math.Abs(value.Value)
+		text := strconv.FormatFloat(abs, 'f', -1, 64)
+		if !strings.Contains(text, ".") {
+			text += ".0" // Stays a float when read back ---
+		}
+		lit := &ast.NumberLiteral{Token: tok(token.NUMBER, text), Value: abs}
+		if math.Signbit(value.Value) {
+			return negate(lit)
+		}
+		return lit
+	case *object.Integer:
+		if value.Value == math.MinInt64 {
+			return nil // Its negation doesn't fit back into an int64 ---
+		}
+		if value.Value < 0 {
+			return negate(&ast.IntegerLiteral{Token: tok(token.NUMBER, strconv.FormatInt(-value.Value, 10)), Value: -value.Value})
+		}
+		return &ast.IntegerLiteral{Token: tok(token.NUMBER, strconv.FormatInt(value.Value, 10)), Value: value.Value}
+	case *object.BigInt:
+		abs := new(big.Int).Abs(value.Value)
+		lit := &ast.BigIntLiteral{Token: tok(token.NUMBER, abs.String()+"n"), Value: abs}
+		if value.Value.Sign() < 0 {
+			return negate(lit)
+		}
+		return lit
+	case *object.Decimal:
+		abs := &object.Decimal{Unscaled: new(big.Int).Abs(value.Unscaled), Scale: value.Scale}
+		lit := &ast.DecimalLiteral{Token: tok(token.NUMBER, abs.Inspect()+"d"), Unscaled: abs.Unscaled, Scale: abs.Scale}
+		if value.Unscaled.Sign() < 0 {
+			return negate(lit)
+		}
+		return lit
+	case *object.String:
+		return &ast.StringLiteral{Token: tok(token.STRING, value.Value), Value: value.Value}
+	case *object.Boolean:
+		if value.Value {
+			return &ast.BooleanExpression{Token: tok(token.TRUE, "true"), Value: true}
+		}
+		return &ast.BooleanExpression{Token: tok(token.FALSE, "false"), Value: false}
+	case *object.Nil:
+		return &ast.NilLiteral{Token: tok(token.NIL, "nil")}
+	case *object.NaN:
+		return &ast.NaNLiteral{Token: tok(token.NOT_A_NUMBER, "NaN")}
+	case *object.Infinity:
+		lit := &ast.InfinityLiteral{Token: tok(token.INFINITY, "Inf"), Sign: 1}
+		if value.Sign < 0 {
+			return negate(lit)
+		}
+		return lit
+	}
+
+	return nil
+}
+
+func isLiteral(node ast.Expression) bool {
+	switch node := node.(type) {
+	case *ast.NumberLiteral, *ast.IntegerLiteral, *ast.BigIntLiteral, *ast.DecimalLiteral, *ast.StringLiteral,
+		*ast.BooleanExpression, *ast.NilLiteral, *ast.NaNLiteral, *ast.InfinityLiteral:
+		return true
+	case *ast.UnaryExpression:
+		// Already how a negative literal is spelled ---
+		if node.Operator.Type != token.MINUS {
+			return false
+		}
+		switch node.Right.(type) {
+		case *ast.NumberLiteral, *ast.IntegerLiteral, *ast.BigIntLiteral, *ast.DecimalLiteral, *ast.InfinityLiteral:
+			return true
+		}
+	}
+
+	return false
+}
+
+func isConstant(node ast.Expression) bool {
+	switch node := node.(type) {
+	case *ast.UnaryExpression:
+		return isConstant(node.Right)
+	case *ast.BinaryExpression:
+		return isConstant(node.Left) && isConstant(node.Right)
+	case *ast.TernaryExpression:
+		return isConstant(node.Condition) && isConstant(node.Consequence) && isConstant(node.Alternative)
+	}
+
+	return isLiteral(node)
+}
+
+// isPure tells expressions that can be dropped when their value is unused,
+// evaluating them can't fail or have an effect
+func isPure(node ast.Expression) bool {
+	switch node := node.(type) {
+	case *ast.FunctionLiteral:
+		return true
+	case *ast.ArrayLiteral:
+		for _, elem := range node.Elements {
+			if !isPure(elem) {
+				return false
+			}
+		}
+		return true
+	}
+
+	return isLiteral(node)
+}
@@ -1,8 +1,9 @@
 package parser
 
 import (
-	"fmt"
+	"math/big"
 	"strconv"
+	"strings"
 
 	"github.com/caelondev/monkey/src/ast"
 	"github.com/caelondev/monkey/src/token"
@@ -46,11 +47,50 @@ func (p *Parser) parseIdentifier() ast.Expression {
 	}
 }
 
+// parseNumberExpression makes floats out of literals with a fraction and
+// integers out of the rest, too big for an int64 means a big integer
 func (p *Parser) parseNumberExpression() ast.Expression {
-	// TODO: Errors are being ignored, this might cause a crash
-	// when the tokenzer invalidly tokenize a numerical token
-	value, _ := strconv.ParseFloat(p.currentToken.Literal, 64)
-	return &ast.NumberLiteral{Token: p.currentToken, Value: value}
+	literal := p.currentToken.Literal
+
+	switch {
+	case strings.HasSuffix(literal, "d"):
+		return p.parseDecimalLiteral(strings.TrimSuffix(literal, "d"))
+	case strings.HasSuffix(literal, "n"):
+		return p.parseBigIntLiteral(strings.TrimSuffix(literal, "n"))
+	}
+
+	if strings.Contains(literal, ".") {
+		value, _ := strconv.ParseFloat(literal, 64)
+		return &ast.NumberLiteral{Token: p.currentToken, Value: value}
+	}
+
+	if value, err := strconv.ParseInt(literal, 10, 64); err == nil {
+		return &ast.IntegerLiteral{Token: p.currentToken, Value: value}
+	}
+
+	return p.parseBigIntLiteral(literal)
+}
+
+func (p *Parser) parseBigIntLiteral(digits string) ast.Expression {
+	value, ok := new(big.Int).SetString(digits, 10)
+	if !ok {
+		p.throwError("[Ln %d:%d] Invalid integer literal '%s'", p.currentToken.Line, p.currentToken.Column, p.currentToken.Literal)
+		return nil
+	}
+
+	return &ast.BigIntLiteral{Token: p.currentToken, Value: value}
+}
+
+func (p *Parser) parseDecimalLiteral(digits string) ast.Expression {
+	whole, fraction, _ := strings.Cut(digits, ".")
+
+	unscaled, ok := new(big.Int).SetString(whole+fraction, 10)
+	if !ok {
+		p.throwError("[Ln %d:%d] Invalid decimal literal '%s'", p.currentToken.Line, p.currentToken.Column, p.currentToken.Literal)
+		return nil
+	}
+
+	return &ast.DecimalLiteral{Token: p.currentToken, Unscaled: unscaled, Scale: len(fraction)}
 }
 
 func (p *Parser) parseUnaryExpression() ast.Expression {
@@ -117,6 +157,57 @@ func (p *Parser) parseNaNLiteral() ast.Expression {
 	return &ast.NaNLiteral{Token: p.currentToken}
 }
 
+// parsePrefixUpdate parses ++x and --x
+func (p *Parser) parsePrefixUpdate() ast.Expression {
+	expr := &ast.UpdateExpression{Token: p.currentToken, Operator: p.currentToken, Prefix: true}
+	p.nextToken() // Eat ++ or --
+
+	expr.Target = p.parseExpression(UNARY)
+	if expr.Target == nil {
+		return nil
+	}
+
+	if !isAssignable(expr.Target) {
+		p.throwError(
+			"[Ln %d:%d] Cannot apply '%s' to '%s', only variables, elements and fields can be updated", expr.Token.Line, expr.Token.Column, expr.Operator.Literal, expr.Target.TokenLiteral())
+		return nil
+	}
+
+	return expr
+}
+
+func (p *Parser) parseHashLiteral() ast.Expression {
+	// Syntax ---
+	//
+	// {}
+	// {<key>: <value>, <key>: <value>}
+	//
+
+	expr := &ast.HashLiteral{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RIGHT_BRACE) {
+		p.nextToken() // Eat { or comma
+
+		key := p.parseExpression(LOWEST)
+		if !p.expectPeek(token.COLON) {
+			return nil
+		}
+
+		p.nextToken() // Eat colon
+		value := p.parseExpression(LOWEST)
+
+		expr.Keys = append(expr.Keys, key)
+		expr.Values = append(expr.Values, value)
+
+		if !p.peekTokenIs(token.RIGHT_BRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	p.nextToken() // Eat last value
+	return expr
+}
+
 func (p *Parser) parseArrayLiteral() ast.Expression {
 	expr := &ast.ArrayLiteral{Token: p.currentToken}
 
@@ -127,14 +218,14 @@ func (p *Parser) parseArrayLiteral() ast.Expression {
 
 	p.nextToken() // Eat [
 
-	firstElem := p.parseExpression(LOWEST)
+	firstElem := p.parseElement()
 	expr.Elements = append(expr.Elements, firstElem)
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken() // Eat expr
 		p.nextToken() // Eat comma
 
-		elem := p.parseExpression(LOWEST)
+		elem := p.parseElement()
 		expr.Elements = append(expr.Elements, elem)
 	}
 
@@ -195,6 +286,44 @@ func (p *Parser) parseCallExpression(left ast.Expression) ast.Expression {
 	return expr
 }
 
+func (p *Parser) parsePipeExpression(left ast.Expression) ast.Expression {
+	// Syntax ---
+	//
+	// <value> |> <function>, is <function>(<value>)
+	// <value> |> <function>(<args>), is <function>(<value>, <args>)
+	//
+
+	if left == nil {
+		return nil
+	}
+
+	expr := &ast.PipeExpression{Token: p.currentToken}
+
+	pre := p.currentPrecedence()
+	p.nextToken() // Eat |>
+
+	stage := p.parseExpression(pre)
+	if stage == nil {
+		return nil
+	}
+
+	if call, ok := stage.(*ast.CallExpression); ok {
+		call.Arguments = append([]ast.Expression{left}, call.Arguments...)
+		expr.Call = call
+		return expr
+	}
+
+	// A plain function gets a call of its own, placed where it was written ---
+	expr.Call = &ast.CallExpression{
+		Token:     token.Token{Type: token.LEFT_PARENTHESIS, Literal: "(", Line: stage.GetLine(), Column: stage.GetColumn()},
+		Function:  stage,
+		Arguments: []ast.Expression{left},
+	}
+	expr.Bare = true
+
+	return expr
+}
+
 func (p *Parser) parseExponentExpression(left ast.Expression) ast.Expression {
 	expr := &ast.BinaryExpression{Token: p.currentToken, Operator: p.currentToken, Left: left}
 
@@ -207,21 +336,20 @@ func (p *Parser) parseExponentExpression(left ast.Expression) ast.Expression {
 }
 
 func (p *Parser) parseAssignmentExpression(left ast.Expression) ast.Expression {
-	// Only identifiers can be reassigned
+	// Only variables and elements can be reassigned
 	if left == nil {
 		return nil
 	}
 
-	ident, ok := left.(*ast.Identifier)
-	if !ok {
-		p.errors = append(p.errors, fmt.Sprintf(
-			"[Ln %d:%d] Cannot reassign to non-identifier '%s'", left.GetLine(), left.GetColumn(), left.TokenLiteral()))
+	if !isAssignable(left) {
+		p.throwError(
+			"[Ln %d:%d] Cannot assign to '%s', only variables, elements and fields can be assigned", left.GetLine(), left.GetColumn(), left.TokenLiteral())
 		return nil
 	}
 
 	expr := &ast.AssignmentExpression{
 		Token:    p.currentToken,
-		Assignee: ident,
+		Assignee: left,
 	}
 
 	p.nextToken()
@@ -231,21 +359,62 @@ func (p *Parser) parseAssignmentExpression(left ast.Expression) ast.Expression {
 
 	// Check if parsing failed
 	if expr.NewValue == nil {
-		p.errors = append(p.errors, fmt.Sprintf(
+		p.throwError(
 			"[Ln %d:%d] Invalid right-hand side in assignment",
-			p.currentToken.Line, p.currentToken.Column))
+			p.currentToken.Line, p.currentToken.Column)
 		return nil
 	}
 
 	return expr
 }
 
+// parsePostfixUpdate parses x++ and x--
+func (p *Parser) parsePostfixUpdate(left ast.Expression) ast.Expression {
+	if left == nil {
+		return nil
+	}
+
+	if !isAssignable(left) {
+		p.throwError(
+			"[Ln %d:%d] Cannot apply '%s' to '%s', only variables, elements and fields can be updated", p.currentToken.Line, p.currentToken.Column, p.currentToken.Literal, left.TokenLiteral())
+		return nil
+	}
+
+	return &ast.UpdateExpression{Token: p.currentToken, Operator: p.currentToken, Target: left}
+}
+
 func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
-	expr := &ast.IndexExpression{Token: p.currentToken, Target: left}
+	// Syntax ---
+	//
+	// <target>[<index>]
+	// <target>[<start>:<end>:<step>], any bound can be left out
+	//
+
+	bracket := p.currentToken
+	optional := bracket.Type == token.QUESTION_BRACKET
+	p.nextToken() // Eat [ or ?[ ---
 
-	p.nextToken() // Eat [ ---
+	var start ast.Expression
+	if !p.currentTokenIs(token.COLON) {
+		start = p.parseExpression(LOWEST)
 
-	expr.Index = p.parseExpression(LOWEST)
+		if !p.peekTokenIs(token.COLON) {
+			if !p.expectPeek(token.RIGHT_BRACKET) {
+				return nil
+			}
+			return &ast.IndexExpression{Token: bracket, Target: left, Index: start, Optional: optional}
+		}
+
+		p.nextToken() // Eat start
+	}
+
+	expr := &ast.SliceExpression{Token: bracket, Target: left, Start: start, Optional: optional}
+	expr.End = p.parseSliceBound()
+
+	if p.peekTokenIs(token.COLON) {
+		p.nextToken() // Eat end
+		expr.Step = p.parseSliceBound()
+	}
 
 	if !p.expectPeek(token.RIGHT_BRACKET) {
 		return nil
@@ -254,40 +423,234 @@ func (p *Parser) parseIndexExpression(left ast.Expression) ast.Expression {
 	return expr
 }
 
+func (p *Parser) parseMemberExpression(left ast.Expression) ast.Expression {
+	// Syntax ---
+	//
+	// <target>.<name>
+	//
+
+	expr := &ast.MemberExpression{Token: p.currentToken, Target: left}
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	expr.Property = p.currentToken.Literal
+
+	return expr
+}
+
+func (p *Parser) parseOptionalLink(left ast.Expression) ast.Expression {
+	// Syntax ---
+	//
+	// <target>?.<name>
+	// <function>?.(<args>)
+	//
+
+	if p.peekTokenIs(token.LEFT_PARENTHESIS) {
+		expr := &ast.CallExpression{Token: p.currentToken, Function: left, Optional: true}
+		p.nextToken() // Eat ?.
+		expr.Arguments = p.parseCallArguments()
+
+		return expr
+	}
+
+	expr := &ast.MemberExpression{Token: p.currentToken, Target: left, Optional: true}
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+	expr.Property = p.currentToken.Literal
+
+	return expr
+}
+
+func (p *Parser) parseMatchExpression() ast.Expression {
+	// Syntax ---
+	//
+	// match <value> {
+	//     <pattern> => <result>,
+	//     <pattern> if <guard> => <result>
+	// }
+	//
+
+	expr := &ast.MatchExpression{Token: p.currentToken}
+
+	p.nextToken() // Eat match
+
+	expr.Subject = p.parseExpression(LOWEST)
+	if expr.Subject == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+
+	for !p.peekTokenIs(token.RIGHT_BRACE) {
+		p.nextToken() // Eat { or comma
+
+		arm := &ast.MatchArm{Pattern: p.parseMatchPattern()}
+		if arm.Pattern == nil {
+			return nil
+		}
+
+		if name := duplicateName(arm.Pattern); name != nil {
+			p.throwError("[Ln %d:%d] '%s' is bound more than once in the same pattern", name.GetLine(), name.GetColumn(), name.Value)
+			return nil
+		}
+
+		if p.peekTokenIs(token.IF) {
+			p.nextToken() // Eat pattern
+			p.nextToken() // Eat if
+
+			arm.Guard = p.parseExpression(LOWEST)
+			if arm.Guard == nil {
+				return nil
+			}
+		}
+
+		if !p.expectPeek(token.FAT_ARROW) {
+			return nil
+		}
+		p.nextToken() // Eat =>
+
+		arm.Body = p.parseExpression(LOWEST)
+		if arm.Body == nil {
+			return nil
+		}
+
+		expr.Arms = append(expr.Arms, arm)
+
+		if !p.peekTokenIs(token.RIGHT_BRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	p.nextToken() // Eat last arm
+	expr.EndToken = p.currentToken
+
+	if len(expr.Arms) == 0 {
+		p.throwError("[Ln %d:%d] Match needs at least one arm", expr.Token.Line, expr.Token.Column)
+		return nil
+	}
+
+	return expr
+}
+
+// parseSliceBound parses the bound after a colon, nil when it's left out
+func (p *Parser) parseSliceBound() ast.Expression {
+	if p.peekTokenIs(token.COLON) || p.peekTokenIs(token.RIGHT_BRACKET) {
+		return nil
+	}
+
+	p.nextToken() // Eat colon
+	return p.parseExpression(LOWEST)
+}
+
 /*
 * [ HELPERS ]
 **/
 
-func (p *Parser) parseFunctionParameters() []*ast.Identifier {
-	idents := make([]*ast.Identifier, 0)
+func (p *Parser) parseFunctionParameters() []*ast.Parameter {
+	// Syntax ---
+	//
+	// (<required>, <optional> = <default>, ...<rest>)
+	//
+	// Any but the rest parameter may be a destructuring pattern
+	//
+
+	params := make([]*ast.Parameter, 0)
 
 	// Check if no args passed
 	if p.peekTokenIs(token.RIGHT_PARENTHESIS) {
 		p.nextToken() // Eat ( ---
-		return idents // Return empty
+		return params // Return empty
 	}
 
-	if !p.expectPeek(token.IDENTIFIER) {
+	for {
+		param := p.parseParameter()
+		if param == nil {
+			return nil
+		}
+
+		if len(params) != 0 {
+			previous := params[len(params)-1]
+
+			if previous.Rest {
+				p.throwError("[Ln %d:%d] Rest parameter '%s' must be the last parameter", previous.GetLine(), previous.GetColumn(), previous.Target.String())
+				return nil
+			}
+			if previous.Default != nil && param.Default == nil && !param.Rest {
+				p.throwError("[Ln %d:%d] Required parameter '%s' cannot follow a parameter with a default", param.GetLine(), param.GetColumn(), param.Target.String())
+				return nil
+			}
+		}
+
+		params = append(params, param)
+
+		// Will run every comma, and automatically
+		// jumps to it
+		if !p.peekTokenIs(token.COMMA) {
+			break
+		}
+		p.nextToken() // Eat last param
+	}
+
+	if !p.expectPeek(token.RIGHT_PARENTHESIS) {
+		return nil
+	}
+
+	targets := make([]ast.Pattern, len(params))
+	for i, param := range params {
+		targets[i] = param.Target
+	}
+	if name := duplicateName(targets...); name != nil {
+		p.throwError("[Ln %d:%d] Parameter '%s' is declared more than once", name.GetLine(), name.GetColumn(), name.Value)
 		return nil
 	}
 
-	firstParam := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-	idents = append(idents, firstParam)
+	return params
+}
+
+// parseParameter parses one parameter, starting from the token before it
+func (p *Parser) parseParameter() *ast.Parameter {
+	param := &ast.Parameter{}
+
+	if p.peekTokenIs(token.ELLIPSIS) {
+		p.nextToken() // Eat ( or comma
+		param.Rest = true
 
-	// Will run every comma, and automatically
-	// jumps to it
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken() // Eat first param
 		if !p.expectPeek(token.IDENTIFIER) {
 			return nil
 		}
+		param.Target = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	} else {
+		p.nextToken() // Eat ( or comma
+
+		param.Target = p.parsePattern()
+		if param.Target == nil {
+			return nil
+		}
+	}
 
-		param := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-		idents = append(idents, param)
+	if !p.peekTokenIs(token.ASSIGNMENT) {
+		return param
 	}
 
-	p.nextToken() // Eat Ident ---
-	return idents
+	if param.Rest {
+		p.throwError("[Ln %d:%d] Rest parameter '%s' cannot have a default", param.GetLine(), param.GetColumn(), param.Target.String())
+		return nil
+	}
+
+	p.nextToken() // Eat name
+	p.nextToken() // Eat =
+
+	param.Default = p.parseExpression(LOWEST)
+	if param.Default == nil {
+		return nil
+	}
+
+	return param
 }
 
 func (p *Parser) parseCallArguments() []ast.Expression {
@@ -302,14 +665,14 @@ func (p *Parser) parseCallArguments() []ast.Expression {
 
 	// Eat ( ---
 	p.nextToken()
-	firstArg := p.parseExpression(LOWEST)
+	firstArg := p.parseArgument()
 	args = append(args, firstArg)
 
 	for p.peekTokenIs(token.COMMA) {
 		p.nextToken() // Advance past Ident
 		p.nextToken() // Advance comma
 
-		arg := p.parseExpression(LOWEST)
+		arg := p.parseArgument()
 		args = append(args, arg)
 	}
 
@@ -317,5 +680,72 @@ func (p *Parser) parseCallArguments() []ast.Expression {
 		return nil
 	}
 
+	// Named arguments come last, each name once ---
+	names := make(map[string]bool)
+	for _, arg := range args {
+		named, ok := arg.(*ast.NamedArgument)
+		switch {
+		case arg == nil:
+			continue
+		case !ok && len(names) != 0:
+			p.throwError("[Ln %d:%d] Positional argument cannot follow a named argument", arg.GetLine(), arg.GetColumn())
+			return nil
+		case ok && names[named.Name]:
+			p.throwError("[Ln %d:%d] Argument '%s' is passed more than once", named.GetLine(), named.GetColumn(), named.Name)
+			return nil
+		case ok:
+			names[named.Name] = true
+		}
+	}
+
 	return args
 }
+
+// parseArgument parses a call argument, which unlike an array element can
+// also be passed by name
+func (p *Parser) parseArgument() ast.Expression {
+	if !p.currentTokenIs(token.IDENTIFIER) || !p.peekTokenIs(token.COLON) {
+		return p.parseElement()
+	}
+
+	arg := &ast.NamedArgument{Token: p.currentToken, Name: p.currentToken.Literal}
+	p.nextToken() // Eat name
+	p.nextToken() // Eat colon
+
+	arg.Value = p.parseExpression(LOWEST)
+	if arg.Value == nil {
+		return nil
+	}
+
+	return arg
+}
+
+// parseElement parses a call argument or an array element, both of which
+// can be spread from an array with ...
+func (p *Parser) parseElement() ast.Expression {
+	if !p.currentTokenIs(token.ELLIPSIS) {
+		return p.parseExpression(LOWEST)
+	}
+
+	spread := &ast.SpreadExpression{Token: p.currentToken}
+	p.nextToken() // Eat ...
+
+	spread.Value = p.parseExpression(LOWEST)
+	if spread.Value == nil {
+		return nil
+	}
+
+	return spread
+}
+
+func isAssignable(expr ast.Expression) bool {
+	switch expr := expr.(type) {
+	case *ast.Identifier:
+		return true
+	case *ast.IndexExpression:
+		return !expr.Optional
+	case *ast.MemberExpression:
+		return !expr.Optional
+	}
+	return false
+}
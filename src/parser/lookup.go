@@ -6,9 +6,15 @@ const (
 	_ int = iota
 	LOWEST
 	ASSIGNMENT
+	PIPELINE
 	TERNARY
+	NULLISH
 	EQUALITY
 	COMPARISON
+	BITWISE_OR
+	BITWISE_XOR
+	BITWISE_AND
+	SHIFT
 	ADDITIVE
 	MULTIPLICATIVE
 	EXPONENTIATION
@@ -23,15 +29,34 @@ var precedence = map[token.TokenType]int{
 	token.GREATER:          COMPARISON,
 	token.LESS_EQUAL:       COMPARISON,
 	token.GREATER_EQUAL:    COMPARISON,
+	token.PIPE:             BITWISE_OR,
+	token.XOR:              BITWISE_XOR,
+	token.AMPERSAND:        BITWISE_AND,
+	token.SHIFT_LEFT:       SHIFT,
+	token.SHIFT_RIGHT:      SHIFT,
 	token.PLUS:             ADDITIVE,
 	token.MINUS:            ADDITIVE,
 	token.STAR:             MULTIPLICATIVE,
 	token.SLASH:            MULTIPLICATIVE,
+	token.PERCENT:          MULTIPLICATIVE,
+	token.FLOOR_DIV:        MULTIPLICATIVE,
 	token.CARET:            EXPONENTIATION,
 	token.LEFT_PARENTHESIS: CALL,
 	token.IF:               TERNARY,
 	token.ASSIGNMENT:       ASSIGNMENT,
+	token.PLUS_ASSIGN:      ASSIGNMENT,
+	token.MINUS_ASSIGN:     ASSIGNMENT,
+	token.STAR_ASSIGN:      ASSIGNMENT,
+	token.SLASH_ASSIGN:     ASSIGNMENT,
+	token.CARET_ASSIGN:     ASSIGNMENT,
+	token.INCREMENT:        CALL,
+	token.DECREMENT:        CALL,
 	token.LEFT_BRACKET:     CALL,
+	token.DOT:              CALL,
+	token.QUESTION_DOT:     CALL,
+	token.QUESTION_BRACKET: CALL,
+	token.NULLISH:          NULLISH,
+	token.PIPELINE:         PIPELINE,
 }
 
 func (p *Parser) registerPrefix(tokenType token.TokenType, fn prefixParseFn) {
@@ -42,6 +67,16 @@ func (p *Parser) registerInfix(tokenType token.TokenType, fn infixParseFn) {
 	p.infixParseFns[tokenType] = fn
 }
 
+// OperatorPrecedence exposes the binding power of an infix operator to
+// tools that need to print expressions back with minimal parentheses
+func OperatorPrecedence(tokenType token.TokenType) int {
+	if p, ok := precedence[tokenType]; ok {
+		return p
+	}
+
+	return LOWEST
+}
+
 // Right 10(+)12
 func (p *Parser) peekPrecedence() int {
 	if p, ok := precedence[p.peekToken.Type]; ok {
@@ -69,8 +104,30 @@ func (p *Parser) createLookupTable() {
 	p.registerPrefix(token.LEFT_BRACKET, p.parseArrayLiteral)
 	p.registerInfix(token.LEFT_BRACKET, p.parseIndexExpression) // Indexing
 
+	// Hash
+	p.registerPrefix(token.LEFT_BRACE, p.parseHashLiteral)
+
+	// Members
+	p.registerInfix(token.DOT, p.parseMemberExpression)
+
+	// Optional chaining
+	p.registerInfix(token.QUESTION_DOT, p.parseOptionalLink)
+	p.registerInfix(token.QUESTION_BRACKET, p.parseIndexExpression)
+	p.registerInfix(token.NULLISH, p.parseBinaryExpression)
+
+	// Pipeline
+	p.registerInfix(token.PIPELINE, p.parsePipeExpression)
+
+	// Match
+	p.registerPrefix(token.MATCH, p.parseMatchExpression)
+
 	p.registerPrefix(token.BANG, p.parseUnaryExpression)
 	p.registerPrefix(token.MINUS, p.parseUnaryExpression)
+	p.registerPrefix(token.TILDE, p.parseUnaryExpression)
+	p.registerPrefix(token.INCREMENT, p.parsePrefixUpdate)
+	p.registerPrefix(token.DECREMENT, p.parsePrefixUpdate)
+	p.registerInfix(token.INCREMENT, p.parsePostfixUpdate)
+	p.registerInfix(token.DECREMENT, p.parsePostfixUpdate)
 
 	p.registerPrefix(token.NIL, p.parseNilLiteral)
 	p.registerPrefix(token.NOT_A_NUMBER, p.parseNaNLiteral)
@@ -82,8 +139,16 @@ func (p *Parser) createLookupTable() {
 	p.registerInfix(token.MINUS, p.parseBinaryExpression)
 	p.registerInfix(token.SLASH, p.parseBinaryExpression)
 	p.registerInfix(token.STAR, p.parseBinaryExpression)
+	p.registerInfix(token.PERCENT, p.parseBinaryExpression)
+	p.registerInfix(token.FLOOR_DIV, p.parseBinaryExpression)
 	p.registerInfix(token.CARET, p.parseExponentExpression)
 
+	p.registerInfix(token.AMPERSAND, p.parseBinaryExpression)
+	p.registerInfix(token.PIPE, p.parseBinaryExpression)
+	p.registerInfix(token.XOR, p.parseBinaryExpression)
+	p.registerInfix(token.SHIFT_LEFT, p.parseBinaryExpression)
+	p.registerInfix(token.SHIFT_RIGHT, p.parseBinaryExpression)
+
 	p.registerInfix(token.EQUAL, p.parseBinaryExpression)
 	p.registerInfix(token.NOT_EQUAL, p.parseBinaryExpression)
 	p.registerInfix(token.LESS, p.parseBinaryExpression)
@@ -96,4 +161,9 @@ func (p *Parser) createLookupTable() {
 	p.registerPrefix(token.FUNCTION, p.parseFunctionLiteral)
 	p.registerInfix(token.LEFT_PARENTHESIS, p.parseCallExpression)
 	p.registerInfix(token.ASSIGNMENT, p.parseAssignmentExpression)
+	p.registerInfix(token.PLUS_ASSIGN, p.parseAssignmentExpression)
+	p.registerInfix(token.MINUS_ASSIGN, p.parseAssignmentExpression)
+	p.registerInfix(token.STAR_ASSIGN, p.parseAssignmentExpression)
+	p.registerInfix(token.SLASH_ASSIGN, p.parseAssignmentExpression)
+	p.registerInfix(token.CARET_ASSIGN, p.parseAssignmentExpression)
 }
@@ -20,6 +20,7 @@ type Parser struct {
 	peekToken    token.Token
 	errors       []string
 	hadError     bool
+	unrecovered  int // Errors thrown since the last statement boundary ---
 
 	prefixParseFns map[token.TokenType]prefixParseFn
 	infixParseFns  map[token.TokenType]infixParseFn
@@ -107,6 +108,8 @@ func (p *Parser) noPrefixParseFnError(t token.TokenType) {
 }
 
 func (p *Parser) throwError(format string, a ...interface{}) {
+	p.unrecovered++
+
 	if p.hadError {
 		return
 	}
@@ -115,3 +118,16 @@ func (p *Parser) throwError(format string, a ...interface{}) {
 	msg := fmt.Sprintf(format, a...)
 	p.errors = append(p.errors, msg)
 }
+
+// synchronize skips the rest of a broken statement so parsing can resume at
+// the next one. This keeps a half-typed file parseable for the tooling
+func (p *Parser) synchronize() {
+	for !p.currentTokenIs(token.SEMICOLON) && !p.currentTokenIs(token.RIGHT_BRACE) && !p.currentTokenIs(token.EOF) {
+		switch p.peekToken.Type {
+		case token.VAR, token.CONST, token.FUNCTION, token.STRUCT, token.ENUM, token.IF, token.SWITCH, token.CASE, token.DEFAULT, token.RETURN, token.ASSIGN, token.EOF:
+			return
+		}
+
+		p.nextToken()
+	}
+}
@@ -6,17 +6,39 @@ import (
 )
 
 func (p *Parser) parseStatement() ast.Statement {
+	stmt := p.parseStatementKind()
+
+	if p.unrecovered == 0 {
+		return stmt
+	}
+
+	// Broken statements are dropped whole, so the AST never holds ---
+	// half-built nodes, then the next statement gets a clean slate ---
+	p.synchronize()
+	p.unrecovered = 0
+	p.hadError = false
+
+	return nil
+}
+
+func (p *Parser) parseStatementKind() ast.Statement {
 	switch p.currentToken.Type {
-	case token.VAR:
+	case token.VAR, token.CONST:
 		return p.parseVarStatement()
 	case token.RETURN:
 		return p.parseReturnStatement()
 	case token.IF:
 		return p.parseIfStatements()
+	case token.SWITCH:
+		return p.parseSwitchStatement()
 	case token.ASSIGN:
 		return p.parseBatchAssignStatement()
 	case token.FUNCTION:
 		return p.parseFunctionStatement()
+	case token.STRUCT:
+		return p.parseStructStatement()
+	case token.ENUM:
+		return p.parseEnumStatement()
 	default:
 		return p.parseExpressionStatement()
 	}
@@ -41,34 +63,42 @@ func (p *Parser) parseVarStatement() *ast.VarStatement {
 	// var <Identifier>;
 	// var <Identifier>, <Identifier>;
 	//
-	// Null Vals
-	// var <Identifier> = <expr>;
-	// var <Identifier>, <Identifier> = <expr>;
+	// Destructure the value, several targets unpack an array
+	// var <Pattern> = <expr>;
+	// var <Pattern>, <Pattern> = <expr>;
+	//
+	// A pattern is a name, [<Pattern>, ..., ...<Identifier>]
+	// or {<key>, <key>: <Pattern>, ...<Identifier>}
+	//
+	// Constants always need a value
+	// const <Identifier> = <expr>;
 	//
 
-	stmt := &ast.VarStatement{Token: p.currentToken}
+	stmt := &ast.VarStatement{Token: p.currentToken, Constant: p.currentTokenIs(token.CONST)}
 
-	if !p.expectPeek(token.IDENTIFIER) {
+	stmt.Targets = p.parsePatterns()
+	if stmt.Targets == nil {
 		return nil
 	}
 
-	// First var
-	stmt.Names = append(stmt.Names, &ast.Identifier{
-		Token: p.currentToken,
-		Value: p.currentToken.Literal,
-	})
-
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken() // Eat Identifier
-		p.nextToken() // Eat comma
+	if name := duplicateName(stmt.Targets...); name != nil {
+		p.throwError("[Ln %d:%d] Cannot declare '%s' more than once in the same statement", name.GetLine(), name.GetColumn(), name.Value)
+		return nil
+	}
 
-		stmt.Names = append(stmt.Names, &ast.Identifier{
-			Token: p.currentToken,
-			Value: p.currentToken.Literal,
-		})
+	if p.peekTokenIs(token.SEMICOLON) && stmt.Constant {
+		p.throwError("[Ln %d:%d] Constant '%s' needs a value", p.currentToken.Line, p.currentToken.Column, stmt.Targets[0].String())
+		return nil
 	}
 
 	if p.peekTokenIs(token.SEMICOLON) {
+		for _, target := range stmt.Targets {
+			if _, ok := target.(*ast.Identifier); !ok {
+				p.throwError("[Ln %d:%d] Destructuring pattern '%s' needs a value", target.GetLine(), target.GetColumn(), target.String())
+				return nil
+			}
+		}
+
 		p.nextToken() // Eat last var name
 		stmt.Value = &ast.NilLiteral{Token: p.currentToken}
 		return stmt
@@ -119,11 +149,14 @@ func (p *Parser) parseBlockStatement() *ast.BlockStatement {
 		stmt := p.parseStatement()
 		if stmt != nil {
 			block.Statements = append(block.Statements, stmt)
+		} else if p.currentTokenIs(token.RIGHT_BRACE) {
+			break // A broken statement ran into the end of the block ---
 		}
 
 		p.nextToken() // Advance next statement
 	}
 
+	block.EndToken = p.currentToken
 	return block
 }
 
@@ -205,25 +238,128 @@ func (p *Parser) parseIfStatements() *ast.IfStatement {
 	return stmt
 }
 
-func (p *Parser) parseBatchAssignStatement() *ast.BatchAssignmentStatement {
-	stmt := &ast.BatchAssignmentStatement{Token: p.currentToken}
+func (p *Parser) parseSwitchStatement() *ast.SwitchStatement {
+	// Syntax ---
+	//
+	// switch (value) {
+	//     case 1, 2: stmt; stmt;
+	//     case "x": stmt;
+	//     default: stmt;
+	// }
+	//
+	// Only the first matching case runs, there is no fallthrough ---
+	//
+	stmt := &ast.SwitchStatement{Token: p.currentToken}
 
-	if !p.expectPeek(token.IDENTIFIER) {
+	if !p.expectPeek(token.LEFT_PARENTHESIS) {
 		return nil
 	}
+	p.nextToken() // Eat (
 
-	firstAssignee := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-	stmt.Assignees = append(stmt.Assignees, firstAssignee)
+	stmt.Subject = p.parseExpression(LOWEST)
+	if stmt.Subject == nil {
+		return nil
+	}
+
+	if !p.expectPeek(token.RIGHT_PARENTHESIS) || !p.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+	p.nextToken() // Eat {
 
-	for p.peekTokenIs(token.COMMA) {
-		p.nextToken() // Eat first assignee
+	hasDefault := false
 
-		if !p.expectPeek(token.IDENTIFIER) {
+	for !p.currentTokenIs(token.RIGHT_BRACE) && !p.currentTokenIs(token.EOF) {
+		c := &ast.SwitchCase{Token: p.currentToken}
+
+		switch p.currentToken.Type {
+		case token.CASE:
+			c.Values = p.parseCaseValues()
+			if c.Values == nil {
+				return nil
+			}
+		case token.DEFAULT:
+			if hasDefault {
+				p.throwError(
+					"[Ln %d:%d] Switch can only have one default",
+					p.currentToken.Line,
+					p.currentToken.Column,
+				)
+				return nil
+			}
+			hasDefault = true
+		default:
+			p.throwError(
+				"[Ln %d:%d] Expected case or default inside switch, got '%s' instead",
+				p.currentToken.Line,
+				p.currentToken.Column,
+				p.currentToken.Literal,
+			)
+			return nil
+		}
+
+		if !p.expectPeek(token.COLON) {
 			return nil
 		}
 
-		assignee := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
-		stmt.Assignees = append(stmt.Assignees, assignee)
+		c.Body = &ast.BlockStatement{Token: p.currentToken, Statements: make([]ast.Statement, 0)}
+		p.nextToken() // Eat :
+
+		for !p.currentTokenIs(token.CASE) && !p.currentTokenIs(token.DEFAULT) &&
+			!p.currentTokenIs(token.RIGHT_BRACE) && !p.currentTokenIs(token.EOF) {
+			body := p.parseStatement()
+			if body != nil {
+				c.Body.Statements = append(c.Body.Statements, body)
+			} else if p.currentTokenIs(token.RIGHT_BRACE) {
+				break // A broken statement ran into the end of the switch ---
+			}
+
+			p.nextToken() // Advance next statement
+		}
+
+		c.Body.EndToken = p.currentToken
+		stmt.Cases = append(stmt.Cases, c)
+	}
+
+	if !p.currentTokenIs(token.RIGHT_BRACE) {
+		p.throwError(
+			"[Ln %d:%d] Expected '}' to close the switch, got '%s' instead",
+			p.currentToken.Line,
+			p.currentToken.Column,
+			p.currentToken.Literal,
+		)
+		return nil
+	}
+
+	stmt.EndToken = p.currentToken
+	return stmt
+}
+
+// parseCaseValues reads the comma separated values after case
+func (p *Parser) parseCaseValues() []ast.Expression {
+	values := []ast.Expression{}
+
+	for {
+		p.nextToken() // Eat case or comma
+
+		value := p.parseExpression(LOWEST)
+		if value == nil {
+			return nil
+		}
+		values = append(values, value)
+
+		if !p.peekTokenIs(token.COMMA) {
+			return values
+		}
+		p.nextToken() // Eat value
+	}
+}
+
+func (p *Parser) parseBatchAssignStatement() *ast.BatchAssignmentStatement {
+	stmt := &ast.BatchAssignmentStatement{Token: p.currentToken}
+
+	stmt.Assignees = p.parsePatterns()
+	if stmt.Assignees == nil {
+		return nil
 	}
 
 	if !p.expectPeek(token.ASSIGNMENT) {
@@ -264,3 +400,446 @@ func (p *Parser) parseFunctionStatement() *ast.FunctionDeclarationStatement {
 
 	return stmt
 }
+
+func (p *Parser) parseStructStatement() *ast.StructStatement {
+	// SYNTAX ---
+	//
+	// struct <Identifier> {
+	//     <field>, <field> = <default>;
+	//     fn <method>(<params>) { <body> }
+	// }
+	//
+	// Fields are plain names, methods reach the instance through self
+	//
+
+	stmt := &ast.StructStatement{Token: p.currentToken}
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	if !p.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+
+	members := make(map[string]bool)
+
+	for !p.peekTokenIs(token.RIGHT_BRACE) {
+		p.nextToken() // Eat { or the last member
+
+		var name *ast.Identifier
+
+		switch p.currentToken.Type {
+		case token.COMMA, token.SEMICOLON:
+			continue
+		case token.IDENTIFIER:
+			field := p.parseField(stmt)
+			if field == nil {
+				return nil
+			}
+			stmt.Fields = append(stmt.Fields, field)
+			name = field.Ident()
+		case token.FUNCTION:
+			method := p.parseFunctionStatement()
+			if method == nil {
+				return nil
+			}
+			stmt.Methods = append(stmt.Methods, method)
+			name = method.Name
+		default:
+			p.throwError("[Ln %d:%d] Expected a field or a method in struct '%s', got '%s' instead", p.currentToken.Line, p.currentToken.Column, stmt.Name.Value, p.currentToken.Literal)
+			return nil
+		}
+
+		if members[name.Value] {
+			p.throwError("[Ln %d:%d] Struct '%s' already has a member named '%s'", name.Token.Line, name.Token.Column, stmt.Name.Value, name.Value)
+			return nil
+		}
+		members[name.Value] = true
+	}
+
+	p.nextToken() // Eat last member
+	stmt.EndToken = p.currentToken
+
+	return stmt
+}
+
+// parseField parses one field of a struct, starting at its name
+func (p *Parser) parseField(stmt *ast.StructStatement) *ast.Parameter {
+	field := &ast.Parameter{Target: &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}}
+
+	if p.peekTokenIs(token.ASSIGNMENT) {
+		p.nextToken() // Eat name
+		p.nextToken() // Eat =
+
+		field.Default = p.parseExpression(LOWEST)
+		if field.Default == nil {
+			return nil
+		}
+	}
+
+	if n := len(stmt.Fields); n != 0 && stmt.Fields[n-1].Default != nil && field.Default == nil {
+		p.throwError("[Ln %d:%d] Required field '%s' cannot follow a field with a default", field.GetLine(), field.GetColumn(), field.Target.String())
+		return nil
+	}
+
+	if !p.peekTokenIs(token.COMMA) && !p.peekTokenIs(token.SEMICOLON) && !p.peekTokenIs(token.RIGHT_BRACE) {
+		p.throwError("[Ln %d:%d] Expected ',' or ';' after field '%s', got '%s' instead", p.currentToken.Line, p.currentToken.Column, field.Target.String(), p.peekToken.Literal)
+		return nil
+	}
+
+	return field
+}
+
+func (p *Parser) parseEnumStatement() *ast.EnumStatement {
+	// SYNTAX ---
+	//
+	// enum <Identifier> { <Variant>, <Variant>(<field>, <field>) }
+	//
+
+	stmt := &ast.EnumStatement{Token: p.currentToken}
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil
+	}
+
+	stmt.Name = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+	if !p.expectPeek(token.LEFT_BRACE) {
+		return nil
+	}
+
+	variants := make(map[string]bool)
+
+	for !p.peekTokenIs(token.RIGHT_BRACE) {
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+
+		variant := &ast.EnumVariant{Name: &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}}
+
+		if variants[variant.Name.Value] {
+			p.throwError("[Ln %d:%d] Enum '%s' already has a variant named '%s'", variant.GetLine(), variant.GetColumn(), stmt.Name.Value, variant.Name.Value)
+			return nil
+		}
+		variants[variant.Name.Value] = true
+
+		if p.peekTokenIs(token.LEFT_PARENTHESIS) {
+			p.nextToken() // Eat name
+
+			if !p.parseVariantFields(variant) {
+				return nil
+			}
+		}
+
+		stmt.Variants = append(stmt.Variants, variant)
+
+		if !p.peekTokenIs(token.RIGHT_BRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	p.nextToken() // Eat last variant
+	stmt.EndToken = p.currentToken
+
+	return stmt
+}
+
+// parseVariantFields parses the names a variant carries, starting at (
+func (p *Parser) parseVariantFields(variant *ast.EnumVariant) bool {
+	for !p.peekTokenIs(token.RIGHT_PARENTHESIS) {
+		if !p.expectPeek(token.IDENTIFIER) {
+			return false
+		}
+
+		field := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+		for _, previous := range variant.Fields {
+			if previous.Value == field.Value {
+				p.throwError("[Ln %d:%d] Variant '%s' already has a field named '%s'", field.Token.Line, field.Token.Column, variant.Name.Value, field.Value)
+				return false
+			}
+		}
+		variant.Fields = append(variant.Fields, field)
+
+		if !p.peekTokenIs(token.RIGHT_PARENTHESIS) && !p.expectPeek(token.COMMA) {
+			return false
+		}
+	}
+
+	p.nextToken() // Eat last field
+	return true
+}
+
+// parsePatterns parses the comma separated targets of var and assign,
+// starting from the keyword
+// duplicateName finds a name that patterns bind twice, the second binding
+// would quietly overwrite the first
+func duplicateName(patterns ...ast.Pattern) *ast.Identifier {
+	seen := make(map[string]bool)
+
+	for _, pattern := range patterns {
+		for _, name := range ast.PatternNames(pattern) {
+			if seen[name.Value] {
+				return name
+			}
+			seen[name.Value] = true
+		}
+	}
+
+	return nil
+}
+
+func (p *Parser) parsePatterns() []ast.Pattern {
+	var patterns []ast.Pattern
+
+	for {
+		p.nextToken() // Eat keyword or comma
+
+		pattern := p.parsePattern()
+		if pattern == nil {
+			return nil
+		}
+		patterns = append(patterns, pattern)
+
+		if !p.peekTokenIs(token.COMMA) {
+			return patterns
+		}
+		p.nextToken() // Eat last pattern
+	}
+}
+
+// parsePattern parses what a value can be bound to, starting at its first
+// token: a name, [a, b, ...rest] or {key, key: pattern, ...rest}
+func (p *Parser) parsePattern() ast.Pattern {
+	switch p.currentToken.Type {
+	case token.IDENTIFIER:
+		return &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	case token.LEFT_BRACKET:
+		return p.parseArrayPattern(p.parsePattern)
+	case token.LEFT_BRACE:
+		return p.parseHashPattern(p.parsePattern)
+	}
+
+	p.throwError("[Ln %d:%d] Expected a name or a destructuring pattern, got '%s' instead", p.currentToken.Line, p.currentToken.Column, p.currentToken.Literal)
+	return nil
+}
+
+// parseArrayPattern and parseHashPattern take the parser of their elements,
+// so match arms can nest their own patterns
+func (p *Parser) parseArrayPattern(element func() ast.Pattern) ast.Pattern {
+	pattern := &ast.ArrayPattern{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RIGHT_BRACKET) {
+		p.nextToken() // Eat [ or comma
+
+		rest, ok := p.parsePatternRest(pattern.Rest)
+		switch {
+		case !ok:
+			return nil
+		case rest != nil:
+			pattern.Rest = rest
+		default:
+			elem := element()
+			if elem == nil {
+				return nil
+			}
+			pattern.Elements = append(pattern.Elements, elem)
+		}
+
+		if !p.peekTokenIs(token.RIGHT_BRACKET) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	p.nextToken() // Eat last element
+	return pattern
+}
+
+func (p *Parser) parseHashPattern(element func() ast.Pattern) ast.Pattern {
+	pattern := &ast.HashPattern{Token: p.currentToken}
+
+	for !p.peekTokenIs(token.RIGHT_BRACE) {
+		p.nextToken() // Eat { or comma
+
+		rest, ok := p.parsePatternRest(pattern.Rest)
+		if !ok {
+			return nil
+		}
+		if rest != nil {
+			pattern.Rest = rest
+			if !p.peekTokenIs(token.RIGHT_BRACE) && !p.expectPeek(token.COMMA) {
+				return nil
+			}
+			continue
+		}
+
+		if !p.currentTokenIs(token.IDENTIFIER) && !p.currentTokenIs(token.STRING) {
+			p.throwError("[Ln %d:%d] Expected a key in hash pattern, got '%s' instead", p.currentToken.Line, p.currentToken.Column, p.currentToken.Literal)
+			return nil
+		}
+
+		key := &ast.StringLiteral{Token: p.currentToken, Value: p.currentToken.Literal}
+		var value ast.Pattern
+
+		switch {
+		case p.peekTokenIs(token.COLON):
+			p.nextToken() // Eat key
+			p.nextToken() // Eat colon
+
+			value = element()
+			if value == nil {
+				return nil
+			}
+		case key.Token.Type == token.IDENTIFIER:
+			// {name} binds the key to a variable of the same name ---
+			value = &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+		default:
+			p.throwError("[Ln %d:%d] Key \"%s\" needs a name to bind to, as in \"%s\": name", key.GetLine(), key.GetColumn(), key.Value, key.Value)
+			return nil
+		}
+
+		pattern.Keys = append(pattern.Keys, key)
+		pattern.Values = append(pattern.Values, value)
+
+		if !p.peekTokenIs(token.RIGHT_BRACE) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	p.nextToken() // Eat last pair
+	return pattern
+}
+
+// parseMatchPattern parses the pattern of a match arm, starting at its
+// first token. On top of what var takes, it tests literals, skips a value
+// with _ and looks into structs and enum variants
+func (p *Parser) parseMatchPattern() ast.Pattern {
+	switch p.currentToken.Type {
+	case token.IDENTIFIER:
+		switch {
+		case p.currentToken.Literal == "_":
+			return &ast.WildcardPattern{Token: p.currentToken}
+		case p.peekTokenIs(token.LEFT_PARENTHESIS), p.peekTokenIs(token.DOT):
+			return p.parseConstructorPattern()
+		}
+		return &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+	case token.LEFT_BRACKET:
+		return p.parseArrayPattern(p.parseMatchPattern)
+	case token.LEFT_BRACE:
+		return p.parseHashPattern(p.parseMatchPattern)
+	case token.NUMBER, token.STRING, token.TRUE, token.FALSE, token.NIL, token.INFINITY:
+		return p.parseLiteralPattern()
+	case token.MINUS:
+		if p.peekTokenIs(token.NUMBER) || p.peekTokenIs(token.INFINITY) {
+			return p.parseLiteralPattern()
+		}
+	}
+
+	p.throwError("[Ln %d:%d] Expected a pattern, got '%s' instead", p.currentToken.Line, p.currentToken.Column, p.currentToken.Literal)
+	return nil
+}
+
+func (p *Parser) parseLiteralPattern() ast.Pattern {
+	// Parsed above every infix operator, so a guard's if isn't taken ---
+	value := p.parseExpression(UNARY)
+	if value == nil {
+		return nil
+	}
+
+	return &ast.LiteralPattern{Value: value}
+}
+
+// parseConstructorPattern parses Point(x, y: pattern) and Color.Blue(hex),
+// a variant without fields needs no parentheses. Once a field is named, a
+// bare name binds the field of that name, Rect(w: 1, h) is Rect(w: 1, h: h)
+func (p *Parser) parseConstructorPattern() ast.Pattern {
+	pattern := &ast.ConstructorPattern{Type: &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}}
+
+	if p.peekTokenIs(token.DOT) {
+		p.nextToken() // Eat name
+		dot := p.currentToken
+
+		if !p.expectPeek(token.IDENTIFIER) {
+			return nil
+		}
+		pattern.Type = &ast.MemberExpression{Token: dot, Target: pattern.Type, Property: p.currentToken.Literal}
+
+		if !p.peekTokenIs(token.LEFT_PARENTHESIS) {
+			return pattern
+		}
+	}
+
+	p.nextToken() // Eat name
+
+	for !p.peekTokenIs(token.RIGHT_PARENTHESIS) {
+		p.nextToken() // Eat ( or comma
+
+		// After a named field a bare name is short for name: name ---
+		shorthand := len(pattern.Keys) != 0 && p.currentTokenIs(token.IDENTIFIER) &&
+			p.currentToken.Literal != "_" && (p.peekTokenIs(token.COMMA) || p.peekTokenIs(token.RIGHT_PARENTHESIS))
+
+		if p.currentTokenIs(token.IDENTIFIER) && p.peekTokenIs(token.COLON) || shorthand {
+			key := &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}
+
+			for _, previous := range pattern.Keys {
+				if previous.Value == key.Value {
+					p.throwError("[Ln %d:%d] Field '%s' is matched more than once", key.Token.Line, key.Token.Column, key.Value)
+					return nil
+				}
+			}
+
+			var value ast.Pattern = &ast.Identifier{Token: key.Token, Value: key.Value}
+			if !shorthand {
+				p.nextToken() // Eat key
+				p.nextToken() // Eat colon
+
+				if value = p.parseMatchPattern(); value == nil {
+					return nil
+				}
+			}
+
+			pattern.Keys = append(pattern.Keys, key)
+			pattern.Values = append(pattern.Values, value)
+		} else {
+			if len(pattern.Keys) != 0 {
+				p.throwError("[Ln %d:%d] Positional pattern cannot follow a named one", p.currentToken.Line, p.currentToken.Column)
+				return nil
+			}
+
+			elem := p.parseMatchPattern()
+			if elem == nil {
+				return nil
+			}
+			pattern.Elements = append(pattern.Elements, elem)
+		}
+
+		if !p.peekTokenIs(token.RIGHT_PARENTHESIS) && !p.expectPeek(token.COMMA) {
+			return nil
+		}
+	}
+
+	p.nextToken() // Eat last field
+	return pattern
+}
+
+// parsePatternRest parses ...name when the pattern is at one, only one is
+// allowed and nothing may follow it. ok is false after an error
+func (p *Parser) parsePatternRest(previous *ast.Identifier) (rest *ast.Identifier, ok bool) {
+	if previous != nil {
+		p.throwError("[Ln %d:%d] Nothing can follow the rest element '...%s'", p.currentToken.Line, p.currentToken.Column, previous.Value)
+		return nil, false
+	}
+
+	if !p.currentTokenIs(token.ELLIPSIS) {
+		return nil, true
+	}
+
+	if !p.expectPeek(token.IDENTIFIER) {
+		return nil, false
+	}
+
+	return &ast.Identifier{Token: p.currentToken, Value: p.currentToken.Literal}, true
+}
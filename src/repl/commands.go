@@ -0,0 +1,245 @@
+package repl
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/caelondev/monkey/src/lexer"
+	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/parser"
+	"github.com/caelondev/monkey/src/run"
+	"github.com/caelondev/monkey/src/token"
+	"github.com/jwalton/gchalk"
+	"github.com/sanity-io/litter"
+)
+
+type metaCommand struct {
+	name        string
+	usage       string
+	description string
+	run         func(s *session, arg string, out io.Writer)
+}
+
+// session holds the state shared between the REPL loop and its meta-commands
+type session struct {
+	inputs []string
+}
+
+// record keeps a piece of source for :save once it ran without an error,
+// so replaying a saved session doesn't stop where this one didn't
+func (s *session) record(source string, result object.Object, ran bool) bool {
+	if !ran || (result != nil && result.Type() == object.ERROR_OBJECT) {
+		return false
+	}
+
+	s.inputs = append(s.inputs, source)
+	return true
+}
+
+var metaCommands []metaCommand
+
+func init() {
+	// Assigned in init() since :help reads the table itself ---
+	metaCommands = []metaCommand{
+		{"help", ":help", "Show this list of commands", commandHelp},
+		{"env", ":env", "List the session's bindings with their types", commandEnv},
+		{"type", ":type <expr>", "Show the type of an expression's value", commandType},
+		{"ast", ":ast <expr>", "Pretty-print the parsed syntax tree", commandAst},
+		{"tokens", ":tokens <expr>", "Dump the lexer output", commandTokens},
+		{"load", ":load <file>", "Run a file into the session", commandLoad},
+		{"reset", ":reset", "Clear every binding in the session", commandReset},
+		{"save", ":save <file>", "Write the session's inputs to a file", commandSave},
+		{"time", ":time <expr>", "Time the evaluation of an expression", commandTime},
+	}
+}
+
+func isMetaCommand(line string) bool {
+	return strings.HasPrefix(strings.TrimSpace(line), ":")
+}
+
+func (s *session) runMetaCommand(line string, out io.Writer) {
+	line = strings.TrimPrefix(strings.TrimSpace(line), ":")
+	name, arg, _ := strings.Cut(line, " ")
+	arg = strings.TrimSpace(arg)
+
+	for _, command := range metaCommands {
+		if command.name == name {
+			command.run(s, arg, out)
+			return
+		}
+	}
+
+	writeReplError(out, fmt.Sprintf("Unknown command ':%s', type :help for a list of commands", name))
+}
+
+func commandHelp(s *session, arg string, out io.Writer) {
+	for _, command := range metaCommands {
+		io.WriteString(out, fmt.Sprintf("  %-16s %s\n", command.usage, command.description))
+	}
+}
+
+func commandEnv(s *session, arg string, out io.Writer) {
+	env := run.ENVIRONMENT
+
+	for _, name := range env.Names() {
+		value, _ := env.Get(name)
+		io.WriteString(out, fmt.Sprintf("  %s: %s = %s\n", name, value.Type(), colorValue(value)))
+	}
+}
+
+func commandType(s *session, arg string, out io.Writer) {
+	if !requireArgument(":type", arg, out) {
+		return
+	}
+
+	// The expression runs in the session like any input, so :save keeps it ---
+	result, ran := run.RunSource(arg, out)
+	s.record(arg, result, ran)
+
+	if result == nil {
+		return
+	}
+
+	if result.Type() == object.ERROR_OBJECT {
+		writeResult(out, result)
+		return
+	}
+
+	io.WriteString(out, string(result.Type())+"\n")
+}
+
+func commandAst(s *session, arg string, out io.Writer) {
+	if !requireArgument(":ast", arg, out) {
+		return
+	}
+
+	p := parser.New(lexer.New(arg))
+	program := p.ParseProgram()
+
+	if len(p.Errors()) != 0 {
+		for _, msg := range p.Errors() {
+			writeReplError(out, msg)
+		}
+		return
+	}
+
+	options := litter.Options{
+		HidePrivateFields: true,
+		HideZeroValues:    true,
+		StripPackageNames: true,
+		FieldExclusions:   regexp.MustCompile(`^Token$`), // Positions only add noise ---
+	}
+
+	for _, stmt := range program.Statements {
+		io.WriteString(out, options.Sdump(stmt)+"\n")
+	}
+}
+
+func commandTokens(s *session, arg string, out io.Writer) {
+	if !requireArgument(":tokens", arg, out) {
+		return
+	}
+
+	l := lexer.New(arg)
+
+	for {
+		tok := l.NextToken()
+		io.WriteString(out, fmt.Sprintf("  [Ln %d:%d] %-14s %q\n", tok.Line, tok.Column, tok.Type, tok.Literal))
+
+		if tok.Type == token.EOF {
+			return
+		}
+	}
+}
+
+func commandLoad(s *session, arg string, out io.Writer) {
+	if !requireArgument(":load", arg, out) {
+		return
+	}
+
+	bytes, err := os.ReadFile(arg)
+	if err != nil {
+		writeReplError(out, err.Error())
+		return
+	}
+
+	if !utf8.Valid(bytes) {
+		writeReplError(out, "Cannot read non-UTF8 file")
+		return
+	}
+
+	source := string(bytes)
+	result, ran := run.RunSource(source, out)
+
+	// Loaded files become part of the session so :save can replay them ---
+	if !s.record(source, result, ran) {
+		if result != nil && result.Type() == object.ERROR_OBJECT {
+			run.FormatFileError(result.(*object.Error), source, out)
+		}
+		return
+	}
+
+	io.WriteString(out, fmt.Sprintf("Loaded '%s'\n", arg))
+}
+
+func commandReset(s *session, arg string, out io.Writer) {
+	run.ENVIRONMENT = run.NewGlobalEnvironment()
+	s.inputs = nil
+	io.WriteString(out, "Session cleared\n")
+}
+
+func commandSave(s *session, arg string, out io.Writer) {
+	if !requireArgument(":save", arg, out) {
+		return
+	}
+
+	content := strings.Join(s.inputs, "\n")
+	if len(s.inputs) != 0 {
+		content += "\n"
+	}
+
+	if err := os.WriteFile(arg, []byte(content), 0o644); err != nil {
+		writeReplError(out, err.Error())
+		return
+	}
+
+	io.WriteString(out, fmt.Sprintf("Saved %d input(s) to '%s'\n", len(s.inputs), arg))
+}
+
+func commandTime(s *session, arg string, out io.Writer) {
+	if !requireArgument(":time", arg, out) {
+		return
+	}
+
+	start := time.Now()
+	result, ran := run.RunSource(arg, out)
+	elapsed := time.Since(start)
+
+	// Like :type, what was timed stays part of the session ---
+	s.record(arg, result, ran)
+
+	if result != nil {
+		writeResult(out, result)
+	}
+
+	io.WriteString(out, gchalk.Cyan(fmt.Sprintf("Took %s\n", elapsed)))
+}
+
+func requireArgument(usage, arg string, out io.Writer) bool {
+	if arg != "" {
+		return true
+	}
+
+	writeReplError(out, fmt.Sprintf("%s expects an argument", usage))
+	return false
+}
+
+func writeReplError(out io.Writer, msg string) {
+	label := gchalk.WithBold().Red("Repl::Error")
+	io.WriteString(out, label+gchalk.Red(" -> "+msg+"\n"))
+}
@@ -0,0 +1,95 @@
+package repl
+
+import (
+	"strings"
+
+	"github.com/caelondev/monkey/src/lexer"
+	"github.com/caelondev/monkey/src/token"
+	"github.com/jwalton/gchalk"
+)
+
+// highlight colors source code by re-lexing it, so the colors always agree
+// with how the interpreter will actually read the input
+func highlight(source string) string {
+	var out strings.Builder
+
+	l := lexer.New(source)
+	offset := 0
+
+	for {
+		tok := l.NextToken()
+		if tok.Type == token.EOF {
+			break
+		}
+
+		start := tokenOffset(source, tok)
+		if start < offset || start > len(source) {
+			break
+		}
+
+		// Whatever the lexer skipped is whitespace or a comment ---
+		out.WriteString(colorSkipped(source[offset:start]))
+
+		end := start + tokenWidth(source[start:], tok)
+		out.WriteString(colorToken(tok, source[start:end]))
+		offset = end
+	}
+
+	out.WriteString(colorSkipped(source[offset:]))
+	return out.String()
+}
+
+func tokenOffset(source string, tok token.Token) int {
+	offset := 0
+
+	for line := uint(1); line < tok.Line; line++ {
+		next := strings.IndexByte(source[offset:], '\n')
+		if next < 0 {
+			return len(source)
+		}
+		offset += next + 1
+	}
+
+	return offset + int(tok.Column) - 1
+}
+
+func tokenWidth(rest string, tok token.Token) int {
+	switch tok.Type {
+	case token.STRING:
+		return min(len(tok.Literal)+2, len(rest)) // Literal excludes the quotes ---
+	case token.ERROR:
+		return len(rest) // Unterminated strings swallow the rest of the input ---
+	default:
+		return min(len(tok.Literal), len(rest))
+	}
+}
+
+func colorToken(tok token.Token, text string) string {
+	switch tok.Type {
+	case token.FUNCTION, token.VAR, token.CONST, token.IF, token.ELSE, token.RETURN, token.ASSIGN, token.XOR, token.STRUCT, token.ENUM, token.MATCH,
+		token.SWITCH, token.CASE, token.DEFAULT:
+		return gchalk.WithBold().BrightBlue(text)
+	case token.STRING:
+		return gchalk.Green(text)
+	case token.NUMBER:
+		return gchalk.Yellow(text)
+	case token.TRUE, token.FALSE:
+		return gchalk.Blue(text)
+	case token.NIL:
+		return gchalk.Gray(text)
+	case token.NOT_A_NUMBER, token.INFINITY:
+		return gchalk.Magenta(text)
+	case token.ERROR, token.ILLEGAL:
+		return gchalk.Red(text)
+	default:
+		return text
+	}
+}
+
+func colorSkipped(text string) string {
+	if strings.TrimSpace(text) == "" {
+		return text
+	}
+
+	return gchalk.Gray(text)
+}
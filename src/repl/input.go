@@ -0,0 +1,201 @@
+package repl
+
+import (
+	"bufio"
+	"fmt"
+	"io"
+	"os"
+	"unicode/utf8"
+
+	"golang.org/x/term"
+)
+
+type lineReader interface {
+	// readLine returns false once the input is exhausted
+	readLine(prompt string) (string, bool)
+	addHistory(line string)
+}
+
+func newLineReader(in io.Reader, out io.Writer) lineReader {
+	if file, ok := in.(*os.File); ok && term.IsTerminal(int(file.Fd())) {
+		return &terminalReader{file: file, in: bufio.NewReader(file), out: out}
+	}
+
+	return &scannerReader{scanner: bufio.NewScanner(in)}
+}
+
+// ---------------- scannerReader ----------------
+// Used when the input is piped, there is nothing to highlight as it's typed
+
+type scannerReader struct {
+	scanner *bufio.Scanner
+}
+
+func (r *scannerReader) readLine(prompt string) (string, bool) {
+	fmt.Printf("%s", prompt)
+	if !r.scanner.Scan() {
+		return "", false
+	}
+
+	return r.scanner.Text(), true
+}
+
+func (r *scannerReader) addHistory(line string) {}
+
+// ---------------- terminalReader ----------------
+// A small raw-mode line editor that redraws the highlighted line on every key
+
+type terminalReader struct {
+	file    *os.File
+	in      *bufio.Reader
+	out     io.Writer
+	history []string
+}
+
+const (
+	keyCtrlA     = 0x01
+	keyCtrlC     = 0x03
+	keyCtrlD     = 0x04
+	keyCtrlE     = 0x05
+	keyCtrlU     = 0x15
+	keyBackspace = 0x7f
+	keyCtrlH     = 0x08
+	keyEscape    = 0x1b
+)
+
+func (r *terminalReader) addHistory(line string) {
+	if line == "" {
+		return
+	}
+
+	if len(r.history) != 0 && r.history[len(r.history)-1] == line {
+		return
+	}
+
+	r.history = append(r.history, line)
+}
+
+func (r *terminalReader) readLine(prompt string) (string, bool) {
+	fd := int(r.file.Fd())
+
+	// Only raw while typing, evaluation output needs the normal terminal mode ---
+	state, err := term.MakeRaw(fd)
+	if err != nil {
+		return "", false
+	}
+	defer term.Restore(fd, state)
+
+	var line []rune
+	cursor := 0
+	historyIdx := len(r.history)
+
+	redraw := func() {
+		io.WriteString(r.out, "\r\x1b[K"+prompt+highlight(string(line)))
+		if back := len(line) - cursor; back > 0 {
+			io.WriteString(r.out, fmt.Sprintf("\x1b[%dD", back))
+		}
+	}
+
+	redraw()
+
+	for {
+		key, _, err := r.in.ReadRune()
+		if err != nil {
+			io.WriteString(r.out, "\r\n")
+			return "", false
+		}
+
+		switch key {
+		case '\r', '\n':
+			io.WriteString(r.out, "\r\n")
+			return string(line), true
+
+		case keyCtrlC:
+			io.WriteString(r.out, "^C\r\n")
+			line, cursor = nil, 0
+
+		case keyCtrlD:
+			if len(line) == 0 {
+				io.WriteString(r.out, "\r\n")
+				return "", false
+			}
+
+		case keyBackspace, keyCtrlH:
+			if cursor > 0 {
+				line = append(line[:cursor-1], line[cursor:]...)
+				cursor--
+			}
+
+		case keyCtrlA:
+			cursor = 0
+
+		case keyCtrlE:
+			cursor = len(line)
+
+		case keyCtrlU:
+			line, cursor = line[cursor:], 0
+
+		case keyEscape:
+			line, cursor, historyIdx = r.readEscape(line, cursor, historyIdx)
+
+		default:
+			if key < ' ' || key == utf8.RuneError {
+				continue
+			}
+
+			line = append(line[:cursor], append([]rune{key}, line[cursor:]...)...)
+			cursor++
+		}
+
+		redraw()
+	}
+}
+
+// readEscape handles the ANSI sequences sent by arrow, home, end and delete
+func (r *terminalReader) readEscape(line []rune, cursor, historyIdx int) ([]rune, int, int) {
+	if next, _, err := r.in.ReadRune(); err != nil || next != '[' {
+		return line, cursor, historyIdx
+	}
+
+	code, _, err := r.in.ReadRune()
+	if err != nil {
+		return line, cursor, historyIdx
+	}
+
+	switch code {
+	case 'A': // Up ---
+		if historyIdx > 0 {
+			historyIdx--
+			line = []rune(r.history[historyIdx])
+			cursor = len(line)
+		}
+	case 'B': // Down ---
+		if historyIdx < len(r.history)-1 {
+			historyIdx++
+			line = []rune(r.history[historyIdx])
+		} else {
+			historyIdx = len(r.history)
+			line = nil
+		}
+		cursor = len(line)
+	case 'C': // Right ---
+		if cursor < len(line) {
+			cursor++
+		}
+	case 'D': // Left ---
+		if cursor > 0 {
+			cursor--
+		}
+	case 'H':
+		cursor = 0
+	case 'F':
+		cursor = len(line)
+	case '3': // Delete sends ESC [ 3 ~ ---
+		r.in.ReadRune()
+		if cursor < len(line) {
+			line = append(line[:cursor], line[cursor+1:]...)
+		}
+	}
+
+	return line, cursor, historyIdx
+}
@@ -0,0 +1,134 @@
+package repl
+
+import (
+	"strings"
+
+	"github.com/caelondev/monkey/src/object"
+	"github.com/jwalton/gchalk"
+)
+
+const (
+	prettyMaxDepth = 4  // Deeper collections are collapsed to [...] ---
+	prettyMaxWidth = 60 // Collections wider than this are split across lines ---
+	prettyIndent   = "  "
+)
+
+// prettyInspect renders a value the way the REPL shows results: colored by
+// type, with wide or nested collections spread over indented lines
+func prettyInspect(obj object.Object) string {
+	return prettyValue(obj, 0, map[object.Object]bool{})
+}
+
+// seen holds the collections being rendered, one met again inside itself
+// is collapsed like a collection past prettyMaxDepth
+func prettyValue(obj object.Object, depth int, seen map[object.Object]bool) string {
+	switch obj.(type) {
+	case *object.Array, *object.Hash, *object.Instance:
+		if seen[obj] {
+			return gchalk.Gray(collapsed(obj))
+		}
+		seen[obj] = true
+		defer delete(seen, obj)
+	}
+
+	switch obj := obj.(type) {
+	case *object.Array:
+		elements := make([]string, len(obj.Elements))
+		for i, elem := range obj.Elements {
+			elements[i] = prettyValue(elem, depth+1, seen)
+		}
+		return prettyCollection("[", elements, "]", obj.Inspect(), depth)
+	case *object.Hash:
+		pairs := make([]string, 0, len(obj.Order))
+		obj.Each(func(pair *object.HashPair) {
+			pairs = append(pairs, colorValue(pair.Key)+": "+prettyValue(pair.Value, depth+1, seen))
+		})
+		return prettyCollection("{", pairs, "}", obj.Inspect(), depth)
+	case *object.Instance:
+		fields := make([]string, len(obj.Values))
+		for i, value := range obj.Values {
+			fields[i] = obj.Struct.Fields[i].Ident().Value + ": " + prettyValue(value, depth+1, seen)
+		}
+		return prettyCollection(obj.Struct.Name+"(", fields, ")", obj.Inspect(), depth)
+	case *object.Variant:
+		if len(obj.Values) == 0 {
+			return obj.Inspect()
+		}
+
+		fields := make([]string, len(obj.Values))
+		for i, value := range obj.Values {
+			fields[i] = obj.Decl.Fields[i].Value + ": " + prettyValue(value, depth+1, seen)
+		}
+		return prettyCollection(obj.Enum.Name+"."+obj.Decl.Name.Value+"(", fields, ")", obj.Inspect(), depth)
+	default:
+		return colorValue(obj)
+	}
+}
+
+func collapsed(obj object.Object) string {
+	switch obj := obj.(type) {
+	case *object.Array:
+		return "[...]"
+	case *object.Instance:
+		return obj.Struct.Name + "(...)"
+	}
+	return "{...}"
+}
+
+// prettyCollection lays out already rendered items, flat is the uncolored
+// one-line form used to measure the width
+func prettyCollection(open string, items []string, close, flat string, depth int) string {
+	if len(items) == 0 {
+		return open + close
+	}
+
+	if depth >= prettyMaxDepth {
+		return gchalk.Gray(open + "..." + close)
+	}
+
+	multiline := len(flat) > prettyMaxWidth
+
+	for _, item := range items {
+		if strings.Contains(item, "\n") {
+			multiline = true
+		}
+	}
+
+	if !multiline {
+		return open + strings.Join(items, ", ") + close
+	}
+
+	var out strings.Builder
+	out.WriteString(open + "\n")
+
+	for i, item := range items {
+		out.WriteString(prettyIndent)
+		out.WriteString(strings.ReplaceAll(item, "\n", "\n"+prettyIndent))
+		if i != len(items)-1 {
+			out.WriteString(",")
+		}
+		out.WriteString("\n")
+	}
+
+	out.WriteString(close)
+	return out.String()
+}
+
+func colorValue(obj object.Object) string {
+	switch obj.Type() {
+	case object.STRING_OBJECT:
+		return gchalk.Green(obj.Inspect())
+	case object.NUMBER_OBJECT, object.INTEGER_OBJECT, object.BIGINT_OBJECT, object.DECIMAL_OBJECT:
+		return gchalk.Yellow(obj.Inspect())
+	case object.BOOLEAN_OBJECT:
+		return gchalk.Blue(obj.Inspect())
+	case object.NIL_OBJECT:
+		return gchalk.Gray(obj.Inspect())
+	case object.NAN_OBJECT, object.INFINITY_OBJECT:
+		return gchalk.Magenta(obj.Inspect())
+	case object.FUNCTION_OBJECT, object.STRUCT_OBJECT, object.ENUM_OBJECT:
+		return gchalk.Cyan(obj.Inspect())
+	default:
+		return obj.Inspect()
+	}
+}
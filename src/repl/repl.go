@@ -1,8 +1,6 @@
 package repl
 
 import (
-	"bufio"
-	"fmt"
 	"io"
 
 	"github.com/caelondev/monkey/src/object"
@@ -11,31 +9,39 @@ import (
 )
 
 func Start(in io.Reader, out io.Writer) {
-	scanner := bufio.NewScanner(in)
-	var allLines []string
+	reader := newLineReader(in, out)
+	s := &session{}
 
 	for {
-		fmt.Printf(">> ")
-		scanned := scanner.Scan()
-		if !scanned {
+		line, ok := reader.readLine(">> ")
+		if !ok {
 			return
 		}
 
-		line := scanner.Text()
-		allLines = append(allLines, line)
+		reader.addHistory(line)
 
-		result := run.RunSource(line, out)
+		if isMetaCommand(line) {
+			s.runMetaCommand(line, out)
+			continue
+		}
+
+		result, ran := run.RunSource(line, out)
+		s.record(line, result, ran)
 
 		if result != nil {
-			if result.Type() == object.ERROR_OBJECT {
-				err := result.(*object.Error)
-				lineColumn := gchalk.WithBold().Red("Runtime::Error")
-				message := gchalk.Red(" -> " + err.Message + "\n")
-				io.WriteString(out, lineColumn+message)
-			} else {
-				io.WriteString(out, result.Inspect())
-				io.WriteString(out, "\n")
-			}
+			writeResult(out, result)
 		}
 	}
 }
+
+func writeResult(out io.Writer, result object.Object) {
+	if result.Type() == object.ERROR_OBJECT {
+		err := result.(*object.Error)
+		lineColumn := gchalk.WithBold().Red("Runtime::Error")
+		message := gchalk.Red(" -> " + err.Message + "\n")
+		io.WriteString(out, lineColumn+message)
+	} else {
+		io.WriteString(out, prettyInspect(result))
+		io.WriteString(out, "\n")
+	}
+}
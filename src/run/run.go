@@ -7,16 +7,35 @@ import (
 	"strings"
 	"unicode/utf8"
 
+	"github.com/caelondev/monkey/src/analysis"
+	"github.com/caelondev/monkey/src/ast"
 	"github.com/caelondev/monkey/src/evaluation"
+	"github.com/caelondev/monkey/src/format"
 	"github.com/caelondev/monkey/src/lexer"
 	"github.com/caelondev/monkey/src/object"
+	"github.com/caelondev/monkey/src/optimizer"
 	"github.com/caelondev/monkey/src/parser"
 	"github.com/jwalton/gchalk"
 )
 
-var ENVIRONMENT *object.Environment = object.NewEnvironment(nil)
+var evaluator = evaluation.New()
 
-func RunFile(filepath string) {
+var ENVIRONMENT *object.Environment = NewGlobalEnvironment()
+
+// NewGlobalEnvironment makes an empty session scope, the natives stay in
+// the prelude it encloses
+func NewGlobalEnvironment() *object.Environment {
+	return evaluator.NewGlobalEnvironment()
+}
+
+type Options struct {
+	Optimize bool // Fold constants and drop dead code before running ---
+	DumpAST  bool // Print the program as it would run instead of running it ---
+
+	lateGlobals bool
+}
+
+func RunFile(filepath string, options Options) {
 	byte, err := os.ReadFile(filepath)
 	if err != nil {
 		fmt.Printf("An error occurred whilst trying to read file:\n%s", err.Error())
@@ -30,14 +49,20 @@ func RunFile(filepath string) {
 	}
 
 	source := string(byte)
-	result := RunSource(source, os.Stdout)
+	result, _ := runSource(source, os.Stdout, options)
 
 	if result != nil && result.Type() == object.ERROR_OBJECT {
-		formatFileError(result.(*object.Error), source, os.Stdout)
+		FormatFileError(result.(*object.Error), source, os.Stdout)
 	}
 }
 
-func RunSource(source string, out io.Writer) object.Object {
+// RunSource runs one piece of a REPL session in the shared ENVIRONMENT,
+// ran is false when it stopped at a parse or resolve error
+func RunSource(source string, out io.Writer) (result object.Object, ran bool) {
+	return runSource(source, out, Options{lateGlobals: true})
+}
+
+func runSource(source string, out io.Writer, options Options) (object.Object, bool) {
 	l := lexer.New(source)
 	p := parser.New(l)
 	program := p.ParseProgram()
@@ -46,17 +71,37 @@ func RunSource(source string, out io.Writer) object.Object {
 		io.WriteString(out, "An error occured whilst parsing:\n")
 		printParserErrors(out, p.Errors())
 		io.WriteString(out, "\n")
-		return nil
+		return nil, false
+	}
+
+	info := analysis.AnalyzeWith(program, analysis.Options{
+		Globals:     ENVIRONMENT.Names(),
+		LateGlobals: options.lateGlobals,
+	})
+
+	if len(info.Undefined) != 0 || len(info.Reassigned) != 0 {
+		io.WriteString(out, "An error occured whilst resolving:\n")
+		printResolveErrors(out, info.Undefined, "Cannot resolve variable '%s'")
+		printResolveErrors(out, info.Reassigned, "Cannot assign to '%s' as it is a constant")
+		io.WriteString(out, "\n")
+		return nil, false
 	}
 
-	evaluator := evaluation.New()
+	if options.Optimize {
+		optimizer.Optimize(program)
+	}
+
+	if options.DumpAST {
+		io.WriteString(out, format.Program(program))
+		return nil, false
+	}
 
 	result := evaluator.Evaluate(program, ENVIRONMENT)
 
-	return result
+	return result, true
 }
 
-func formatFileError(err *object.Error, source string, out io.Writer) {
+func FormatFileError(err *object.Error, source string, out io.Writer) {
 	lines := strings.Split(source, "\n")
 
 	lineColumn := gchalk.WithBold().Red(fmt.Sprintf("[Ln %d:%d] Runtime::Error", err.Line, err.Column))
@@ -96,3 +141,9 @@ func printParserErrors(out io.Writer, errors []string) {
 		io.WriteString(out, "\t"+msg+"\n")
 	}
 }
+
+func printResolveErrors(out io.Writer, idents []*ast.Identifier, message string) {
+	for _, ident := range idents {
+		io.WriteString(out, fmt.Sprintf("\t[Ln %d:%d] -> "+message+"\n", ident.GetLine(), ident.GetColumn(), ident.Value))
+	}
+}
@@ -7,6 +7,15 @@ type Token struct {
 	Literal string
 	Line    uint
 	Column  uint
+	Trivia  []Comment // Comments found right before this token ---
+}
+
+// Comment is trivia: the parser never sees it, but tools such as the
+// formatter need it to reproduce the source faithfully
+type Comment struct {
+	Text   string // Including the // or /* */ delimiters ---
+	Line   uint
+	Column uint
 }
 
 const (
@@ -27,6 +36,22 @@ const (
 	STAR       = "*"
 	SLASH      = "/"
 	CARET      = "^"
+	PERCENT    = "%"
+	FLOOR_DIV  = "//" // Only after a value, elsewhere // starts a comment. ~/ works anywhere ---
+
+	AMPERSAND   = "&"
+	PIPE        = "|"
+	TILDE       = "~"
+	SHIFT_LEFT  = "<<"
+	SHIFT_RIGHT = ">>"
+
+	PLUS_ASSIGN  = "+="
+	MINUS_ASSIGN = "-="
+	STAR_ASSIGN  = "*="
+	SLASH_ASSIGN = "/="
+	CARET_ASSIGN = "^="
+	INCREMENT    = "++"
+	DECREMENT    = "--"
 
 	LESS          = "<"
 	GREATER       = ">"
@@ -36,6 +61,15 @@ const (
 	// Delimiters
 	COMMA     = ","
 	SEMICOLON = ";"
+	COLON     = ":"
+	ELLIPSIS  = "..."
+	DOT       = "."
+	FAT_ARROW = "=>"
+
+	NULLISH          = "??"
+	PIPELINE         = "|>"
+	QUESTION_DOT     = "?." // a?.b and f?.() ---
+	QUESTION_BRACKET = "?["
 
 	LEFT_PARENTHESIS  = "("
 	RIGHT_PARENTHESIS = ")"
@@ -51,6 +85,7 @@ const (
 	// Reserved keywords
 	FUNCTION     = "FUNCTION"
 	VAR          = "VAR"
+	CONST        = "CONST"
 	TRUE         = "TRUE"
 	FALSE        = "FALSE"
 	IF           = "IF"
@@ -60,18 +95,33 @@ const (
 	INFINITY     = "INFINITY"
 	NOT_A_NUMBER = "NOT_A_NUMBER"
 	ASSIGN       = "ASSIGN"
+	XOR          = "XOR"
+	STRUCT       = "STRUCT"
+	ENUM         = "ENUM"
+	MATCH        = "MATCH"
+	SWITCH       = "SWITCH"
+	CASE         = "CASE"
+	DEFAULT      = "DEFAULT"
 )
 
 var reservedKeywords = map[string]TokenType{
-	"fn":     FUNCTION,
-	"var":    VAR,
-	"true":   TRUE,
-	"false":  FALSE,
-	"if":     IF,
-	"else":   ELSE,
-	"return": RETURN,
-	"nil":    NIL,
-	"assign": ASSIGN,
+	"fn":      FUNCTION,
+	"var":     VAR,
+	"const":   CONST,
+	"true":    TRUE,
+	"false":   FALSE,
+	"if":      IF,
+	"else":    ELSE,
+	"return":  RETURN,
+	"nil":     NIL,
+	"assign":  ASSIGN,
+	"xor":     XOR,
+	"struct":  STRUCT,
+	"enum":    ENUM,
+	"match":   MATCH,
+	"switch":  SWITCH,
+	"case":    CASE,
+	"default": DEFAULT,
 
 	"Inf": INFINITY,
 	"NaN": NOT_A_NUMBER,